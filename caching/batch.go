@@ -0,0 +1,192 @@
+package caching
+
+import (
+	"errors"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+// BatchResult 批量获取的结果，按请求顺序记录每个 key 的命中情况
+type BatchResult struct {
+	manager    *CacheManager
+	bucketName BucketName
+	rawKeys    []string
+	values     [][]byte
+}
+
+// Len 获取请求的 key 数量
+func (b *BatchResult) Len() int {
+	return len(b.rawKeys)
+}
+
+// RawKey 获取指定位置的原始 key
+func (b *BatchResult) RawKey(index int) string {
+	return b.rawKeys[index]
+}
+
+// Hit 判断指定位置的 key 是否命中
+func (b *BatchResult) Hit(index int) bool {
+	return index >= 0 && index < len(b.values) && b.values[index] != nil
+}
+
+// Hits 获取所有命中的位置
+func (b *BatchResult) Hits() []int {
+	indexes := make([]int, 0, len(b.values))
+	for i, value := range b.values {
+		if value != nil {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// Misses 获取所有未命中的位置，调用方只需要加载这些位置对应的数据
+func (b *BatchResult) Misses() []int {
+	indexes := make([]int, 0)
+	for i, value := range b.values {
+		if value == nil {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// Bytes 获取指定位置的缓存字节数组，未命中时返回 ErrCacheMiss
+func (b *BatchResult) Bytes(index int) ([]byte, error) {
+	if index < 0 || index >= len(b.values) {
+		return nil, toolkitError.ErrSliceIndexOutOfRange
+	}
+	if b.values[index] == nil {
+		return nil, toolkitError.ErrCacheMiss
+	}
+	return b.values[index], nil
+}
+
+// Decode 将指定位置的缓存值解码到 result 指针，未命中时返回 ErrCacheMiss
+func (b *BatchResult) Decode(index int, result any) error {
+	bs, err := b.Bytes(index)
+	if err != nil {
+		return err
+	}
+	return b.manager.decode(b.bucketName, b.rawKeys[index], bs, result)
+}
+
+// GetMany 批量获取缓存，keyAppends 中的每一项用于格式化一个 key
+// 缓存桶实现 BatchCacheBucket 时使用一次批量请求，否则逐个获取
+func (c *CacheManager) GetMany(bucketName BucketName, key CacheKey, keyAppends [][]interface{}) (*BatchResult, error) {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return nil, toolkitError.ErrBadBucketName
+	}
+	rawKeys := batchRawKeys(key, keyAppends)
+	result := &BatchResult{manager: c, bucketName: bucketName, rawKeys: rawKeys}
+	if batchBucket, ok := bucket.(BatchCacheBucket); ok {
+		values, err := batchBucket.GetBytesBatch(rawKeys)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != len(rawKeys) {
+			return nil, toolkitError.ErrBatchSizeMismatch
+		}
+		for i, bs := range values {
+			if bs != nil {
+				values[i], _ = expireHard(bs, nil)
+			}
+		}
+		result.values = values
+		c.recordBatchGet(bucketName, values)
+		return result, nil
+	}
+	result.values = make([][]byte, len(rawKeys))
+	for i, rawKey := range rawKeys {
+		bs, err := expireHard(bucket.GetBytes(NewCacheKey(rawKey)))
+		if err != nil {
+			if errors.Is(err, toolkitError.ErrCacheMiss) {
+				continue
+			}
+			return nil, err
+		}
+		if bs == nil {
+			bs = []byte{}
+		}
+		result.values[i] = bs
+	}
+	c.recordBatchGet(bucketName, result.values)
+	return result, nil
+}
+
+// PutMany 批量缓存数据，data 与 keyAppends 按位置一一对应
+func (c *CacheManager) PutMany(bucketName BucketName, key CacheKey, keyAppends [][]interface{}, data []any) error {
+	if len(keyAppends) != len(data) {
+		return toolkitError.ErrBatchSizeMismatch
+	}
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return toolkitError.ErrBadBucketName
+	}
+	rawKeys := batchRawKeys(key, keyAppends)
+	values := make([][]byte, len(data))
+	for i, value := range data {
+		bs, err := c.encode(rawKeys[i], value)
+		if err != nil {
+			return err
+		}
+		values[i] = bs
+	}
+	for _, rawKey := range rawKeys {
+		c.negatives.delete(loadFlightKey(bucketName, rawKey))
+	}
+	if batchBucket, ok := bucket.(BatchCacheBucket); ok {
+		if err := batchBucket.PutBytesBatch(rawKeys, values); err != nil {
+			return err
+		}
+		c.untag(bucketName, rawKeys...)
+		c.record(bucketName, metricPut, len(rawKeys), nil)
+		return nil
+	}
+	for i, rawKey := range rawKeys {
+		if err := bucket.PutBytes(NewCacheKey(rawKey), values[i]); err != nil {
+			return err
+		}
+		c.afterPut(bucketName, rawKey, nil)
+	}
+	return nil
+}
+
+// EvictMany 批量清除缓存
+func (c *CacheManager) EvictMany(bucketName BucketName, key CacheKey, keyAppends [][]interface{}) error {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return toolkitError.ErrBadBucketName
+	}
+	rawKeys := batchRawKeys(key, keyAppends)
+	if _, err := c.evictRawKeys(bucketName, bucket, rawKeys); err != nil {
+		return err
+	}
+	c.publishQuietly(InvalidationEvent{Bucket: bucketName, Kind: InvalidationKeys, Keys: rawKeys})
+	return nil
+}
+
+// recordBatchGet 按批量获取的结果记录命中和未命中次数
+func (c *CacheManager) recordBatchGet(bucketName BucketName, values [][]byte) {
+	hits := 0
+	for _, value := range values {
+		if value != nil {
+			hits++
+		}
+	}
+	c.record(bucketName, metricHit, hits, nil)
+	c.record(bucketName, metricMiss, len(values)-hits, nil)
+}
+
+func batchRawKeys(key CacheKey, keyAppends [][]interface{}) []string {
+	rawKeys := make([]string, len(keyAppends))
+	for i, keyAppend := range keyAppends {
+		rawKeys[i] = key.RawKeyString(keyAppend...)
+	}
+	return rawKeys
+}
@@ -0,0 +1,99 @@
+package caching
+
+import (
+	"errors"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestCacheManagerBatch(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("batch:user:%d")
+	users := []any{testUser{Name: "A", Sex: 1}, testUser{Name: "B", Sex: 2}}
+
+	if err := manager.PutMany(bucketName, key, [][]interface{}{{1}, {2}}, users); err != nil {
+		t.Fatalf("put many: %v", err)
+	}
+	result, err := manager.GetMany(bucketName, key, [][]interface{}{{1}, {3}, {2}})
+	if err != nil {
+		t.Fatalf("get many: %v", err)
+	}
+	if result.Len() != 3 || result.RawKey(1) != "batch:user:3" {
+		t.Fatalf("unexpected batch result keys")
+	}
+	if hits, misses := result.Hits(), result.Misses(); len(hits) != 2 || len(misses) != 1 || misses[0] != 1 {
+		t.Fatalf("unexpected hits %v and misses %v", hits, misses)
+	}
+	var got testUser
+	if err = result.Decode(2, &got); err != nil || got != users[1] {
+		t.Fatalf("unexpected decoded value %+v, %v", got, err)
+	}
+	if err = result.Decode(1, &got); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss for missing key, got %v", err)
+	}
+
+	if err = manager.EvictMany(bucketName, key, [][]interface{}{{1}, {2}}); err != nil {
+		t.Fatalf("evict many: %v", err)
+	}
+	if result, err = manager.GetMany(bucketName, key, [][]interface{}{{1}, {2}}); err != nil || len(result.Misses()) != 2 {
+		t.Fatalf("expected all misses after evict many, got %v, %v", result.Misses(), err)
+	}
+}
+
+func TestCacheManagerBatchUsesBatchBucket(t *testing.T) {
+	bucket, _ := newTestRedisBucket(t, RedisBucketOption{})
+	bucketName := NewBucketName("redis")
+	manager := NewCacheManager().AddBucket(bucketName, bucket)
+	key := NewCacheKey("batch:%s")
+
+	if err := manager.PutMany(bucketName, key, [][]interface{}{{"a"}, {"b"}}, []any{"1", "2"}); err != nil {
+		t.Fatalf("put many: %v", err)
+	}
+	result, err := manager.GetMany(bucketName, key, [][]interface{}{{"a"}, {"missing"}, {"b"}})
+	if err != nil {
+		t.Fatalf("get many: %v", err)
+	}
+	var got string
+	if err = result.Decode(2, &got); err != nil || got != "2" {
+		t.Fatalf("unexpected decoded value %q, %v", got, err)
+	}
+	if !result.Hit(0) || result.Hit(1) {
+		t.Fatalf("unexpected hit flags")
+	}
+}
+
+func TestCacheManagerBatchSizeMismatch(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	err := manager.PutMany(bucketName, NewCacheKey("batch:%d"), [][]interface{}{{1}}, nil)
+	if !errors.Is(err, toolkitError.ErrBatchSizeMismatch) {
+		t.Fatalf("expected ErrBatchSizeMismatch, got %v", err)
+	}
+}
+
+func TestTypedCacheBatch(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	users := NewTypedCache[int, testUser](manager, bucketName, NewCacheKey("typed:batch:%d"))
+	want := []testUser{{Name: "A", Sex: 1}, {Name: "B", Sex: 2}}
+
+	if err := users.PutMany([]int{1, 2}, want); err != nil {
+		t.Fatalf("typed put many: %v", err)
+	}
+	values, misses, err := users.GetMany([]int{1, 3, 2})
+	if err != nil {
+		t.Fatalf("typed get many: %v", err)
+	}
+	if values[0] != want[0] || values[1] != (testUser{}) || values[2] != want[1] {
+		t.Fatalf("unexpected typed values %+v", values)
+	}
+	if len(misses) != 1 || misses[0] != 3 {
+		t.Fatalf("unexpected typed misses %v", misses)
+	}
+
+	if err = users.EvictMany([]int{1, 2}); err != nil {
+		t.Fatalf("typed evict many: %v", err)
+	}
+	if _, misses, _ = users.GetMany([]int{1, 2}); len(misses) != 2 {
+		t.Fatalf("expected all misses after typed evict many, got %v", misses)
+	}
+}
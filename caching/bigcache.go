@@ -2,6 +2,7 @@ package caching
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"time"
 
@@ -17,6 +18,9 @@ func (l log) Printf(format string, v ...interface{}) {
 	logrus.Logrus().Debugln(format, v)
 }
 
+// bigCacheExpireHeaderSize 每个缓存项前置的过期时间头长度，内容为过期时间的 UnixNano，0 表示未单独设置过期时间
+const bigCacheExpireHeaderSize = 8
+
 type BigCacheBucket struct {
 	cache *bigcache.BigCache
 }
@@ -43,24 +47,100 @@ func NewSimpleBigCache(duration time.Duration) (*BigCacheBucket, error) {
 }
 
 func (b *BigCacheBucket) GetBytes(key CacheKey, keyAppend ...interface{}) ([]byte, error) {
-	bytes, err := b.cache.Get(key.RawKeyString(keyAppend...))
+	data, _, err := b.getEntry(key.RawKeyString(keyAppend...))
 	if err != nil {
-		if errors.Is(err, bigcache.ErrEntryNotFound) {
-			return nil, toolkitError.ErrCacheMiss
-		}
 		return nil, err
 	}
-	return bytes, nil
+	return data, nil
 }
 
 func (b *BigCacheBucket) PutBytes(key CacheKey, data []byte, keyAppend ...interface{}) error {
-	err := b.cache.Set(key.RawKeyString(keyAppend...), data)
+	return b.PutBytesWithTTL(key, data, 0, keyAppend...)
+}
+
+// PutBytesWithTTL 设置key对应的字节数组，并指定过期时间
+// 单独设置的过期时间不能超过创建桶时指定的 LifeWindow，超过后缓存项仍会被 BigCache 清除
+func (b *BigCacheBucket) PutBytesWithTTL(key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error {
+	var expireAt int64
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl).UnixNano()
+	}
+	entry := make([]byte, bigCacheExpireHeaderSize+len(data))
+	binary.BigEndian.PutUint64(entry, uint64(expireAt))
+	copy(entry[bigCacheExpireHeaderSize:], data)
+	return b.cache.Set(key.RawKeyString(keyAppend...), entry)
+}
+
+// TTL 获取key剩余的过期时间，返回 0 表示未单独设置过期时间
+func (b *BigCacheBucket) TTL(key CacheKey, keyAppend ...interface{}) (time.Duration, error) {
+	_, expireAt, err := b.getEntry(key.RawKeyString(keyAppend...))
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	if expireAt == 0 {
+		return 0, nil
+	}
+	return time.Until(time.Unix(0, expireAt)), nil
 }
 
 func (b *BigCacheBucket) Evict(key CacheKey, keyAppend ...interface{}) error {
 	return b.cache.Delete(key.RawKeyString(keyAppend...))
 }
+
+// isEntryNotFound 判断是否为 BigCacheBucket 清除不存在的 key 时返回的错误
+func isEntryNotFound(err error) bool {
+	return errors.Is(err, bigcache.ErrEntryNotFound)
+}
+
+// getEntry 读取缓存项并解析过期时间头，已过期的缓存项将被删除并视为未命中
+func (b *BigCacheBucket) getEntry(rawKey string) ([]byte, int64, error) {
+	entry, err := b.cache.Get(rawKey)
+	if err != nil {
+		if errors.Is(err, bigcache.ErrEntryNotFound) {
+			return nil, 0, toolkitError.ErrCacheMiss
+		}
+		return nil, 0, err
+	}
+	data, expireAt, ok := parseBigCacheEntry(entry)
+	if !ok {
+		_ = b.cache.Delete(rawKey)
+		return nil, 0, toolkitError.ErrCacheMiss
+	}
+	return data, expireAt, nil
+}
+
+// parseBigCacheEntry 解析缓存项的过期时间头，缓存项无效或已过期时返回 false
+func parseBigCacheEntry(entry []byte) ([]byte, int64, bool) {
+	if len(entry) < bigCacheExpireHeaderSize {
+		return nil, 0, false
+	}
+	expireAt := int64(binary.BigEndian.Uint64(entry))
+	if expireAt != 0 && time.Now().UnixNano() >= expireAt {
+		return nil, 0, false
+	}
+	return entry[bigCacheExpireHeaderSize:], expireAt, true
+}
+
+// Range 遍历所有未过期的缓存项，遍历期间写入的缓存项不保证被访问到
+func (b *BigCacheBucket) Range(fn func(rawKey string, data []byte, ttl time.Duration) bool) error {
+	iterator := b.cache.Iterator()
+	for iterator.SetNext() {
+		info, err := iterator.Value()
+		if err != nil {
+			// 缓存项在遍历期间被清除或覆盖
+			continue
+		}
+		data, expireAt, ok := parseBigCacheEntry(info.Value())
+		if !ok {
+			continue
+		}
+		var ttl time.Duration
+		if expireAt != 0 {
+			ttl = time.Until(time.Unix(0, expireAt))
+		}
+		if !fn(info.Key(), data, ttl) {
+			return nil
+		}
+	}
+	return nil
+}
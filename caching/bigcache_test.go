@@ -0,0 +1,70 @@
+package caching
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestBigCache(t *testing.T) *BigCacheBucket {
+	t.Helper()
+
+	bucket, err := NewSimpleBigCache(time.Minute)
+	if err != nil {
+		t.Fatalf("new simple big cache: %v", err)
+	}
+	return bucket
+}
+
+func TestBigCacheBucketPutBytesWithTTL(t *testing.T) {
+	bucket := newTestBigCache(t)
+	shortKey := NewCacheKey("token:%d")
+	longKey := NewCacheKey("profile:%d")
+
+	if err := bucket.PutBytesWithTTL(shortKey, []byte("token"), 30*time.Millisecond, 1); err != nil {
+		t.Fatalf("put short ttl: %v", err)
+	}
+	if err := bucket.PutBytesWithTTL(longKey, []byte("profile"), time.Hour, 1); err != nil {
+		t.Fatalf("put long ttl: %v", err)
+	}
+	if got, err := bucket.GetBytes(shortKey, 1); err != nil || string(got) != "token" {
+		t.Fatalf("expected short ttl entry before expiry, got %q, %v", got, err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := bucket.GetBytes(shortKey, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after ttl, got %v", err)
+	}
+	if got, err := bucket.GetBytes(longKey, 1); err != nil || string(got) != "profile" {
+		t.Fatalf("expected long ttl entry to survive, got %q, %v", got, err)
+	}
+}
+
+func TestBigCacheBucketTTL(t *testing.T) {
+	bucket := newTestBigCache(t)
+	key := NewCacheKey("ttl:%s")
+
+	if err := bucket.PutBytes(key, []byte("plain"), "plain"); err != nil {
+		t.Fatalf("put bytes: %v", err)
+	}
+	if ttl, err := bucket.TTL(key, "plain"); err != nil || ttl != 0 {
+		t.Fatalf("expected zero ttl for plain entry, got %v, %v", ttl, err)
+	}
+
+	if err := bucket.PutBytesWithTTL(key, []byte("ttl"), time.Minute, "ttl"); err != nil {
+		t.Fatalf("put bytes with ttl: %v", err)
+	}
+	ttl, err := bucket.TTL(key, "ttl")
+	if err != nil {
+		t.Fatalf("get ttl: %v", err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("unexpected remaining ttl: %v", ttl)
+	}
+
+	if _, err = bucket.TTL(key, "missing"); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss for missing ttl, got %v", err)
+	}
+}
@@ -3,10 +3,12 @@ package caching
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	toolkitError "github.com/acexy/golang-toolkit/error"
 	"github.com/acexy/golang-toolkit/logger"
 	"github.com/acexy/golang-toolkit/util/gob"
+	"golang.org/x/sync/singleflight"
 )
 
 type CacheKey struct {
@@ -24,6 +26,18 @@ type Codec interface {
 	Decode(bs []byte, result any) error
 }
 
+// KeyedCodec 需要感知缓存 key 的 Codec 扩展，CacheManager 会优先使用带原始 key 的方法
+type KeyedCodec interface {
+	Codec
+
+	// EncodeWithKey 将原始 key 对应的缓存值编码为字节数组
+	EncodeWithKey(rawKey string, data any) ([]byte, error)
+
+	// DecodeWithKey 将原始 key 对应的缓存字节数组解码到 result 指针
+	DecodeWithKey(rawKey string, bs []byte, result any) error
+}
+
+// GobCodec 使用 gob 编码缓存值，编码结果不带编码头以兼容历史数据
 type GobCodec struct {
 }
 
@@ -31,8 +45,9 @@ func (g GobCodec) Encode(data any) ([]byte, error) {
 	return gob.Encode(data)
 }
 
+// Decode 解码缓存值，带编码头的数据将使用对应的 Codec 解码
 func (g GobCodec) Decode(bs []byte, result any) error {
-	return gob.Decode(bs, result)
+	return decodeTagged(bs, result)
 }
 
 func defaultCodec(codec Codec) Codec {
@@ -61,9 +76,15 @@ func (m CacheKey) RawKeyString(keyAppend ...interface{}) string {
 }
 
 type CacheManager struct {
-	lock   sync.RWMutex
-	caches map[BucketName]CacheBucket
-	codec  Codec
+	lock      sync.RWMutex
+	caches    map[BucketName]CacheBucket
+	codec     Codec
+	loadGroup singleflight.Group
+	negatives negativeCache
+	counters  map[BucketName]*bucketCounters
+	observer  MetricsObserver
+	tags      map[BucketName]*tagIndex
+	bus       *invalidationBus
 }
 
 type CacheBucket interface {
@@ -78,14 +99,49 @@ type CacheBucket interface {
 	Evict(key CacheKey, keyAppend ...interface{}) error
 }
 
+// TTLCacheBucket 支持单个缓存项过期时间的缓存桶扩展
+type TTLCacheBucket interface {
+	CacheBucket
+
+	// PutBytesWithTTL 设置key对应的字节数组，并指定过期时间，ttl <= 0 表示不单独设置过期时间
+	PutBytesWithTTL(key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error
+
+	// TTL 获取key剩余的过期时间，返回 0 表示未单独设置过期时间
+	TTL(key CacheKey, keyAppend ...interface{}) (time.Duration, error)
+}
+
+// IterableCacheBucket 支持遍历缓存项的缓存桶扩展
+type IterableCacheBucket interface {
+	CacheBucket
+
+	// Range 遍历所有未过期的缓存项，ttl 为剩余的过期时间，0 表示未单独设置过期时间，fn 返回 false 时停止遍历
+	Range(fn func(rawKey string, data []byte, ttl time.Duration) bool) error
+}
+
+// BatchCacheBucket 支持批量操作的缓存桶扩展，适合支持 pipeline 的远程缓存实现
+type BatchCacheBucket interface {
+	CacheBucket
+
+	// GetBytesBatch 批量获取原始 key 对应的值，返回结果与 rawKeys 顺序一致，未命中的位置为 nil
+	GetBytesBatch(rawKeys []string) ([][]byte, error)
+
+	// PutBytesBatch 批量设置原始 key 对应的字节数组
+	PutBytesBatch(rawKeys []string, data [][]byte) error
+
+	// EvictBatch 批量清除原始 key
+	EvictBatch(rawKeys []string) error
+}
+
 func NewCacheManager(codec ...Codec) *CacheManager {
 	var selectedCodec Codec
 	if len(codec) > 0 {
 		selectedCodec = codec[0]
 	}
 	return &CacheManager{
-		caches: make(map[BucketName]CacheBucket),
-		codec:  defaultCodec(selectedCodec),
+		caches:   make(map[BucketName]CacheBucket),
+		codec:    defaultCodec(selectedCodec),
+		counters: make(map[BucketName]*bucketCounters),
+		tags:     make(map[BucketName]*tagIndex),
 	}
 }
 
@@ -96,6 +152,8 @@ func (c *CacheManager) AddBucket(bucketName BucketName, bucket CacheBucket) *Cac
 
 	if _, flag := c.caches[bucketName]; !flag {
 		c.caches[bucketName] = bucket
+		c.counters[bucketName] = &bucketCounters{}
+		c.tags[bucketName] = newTagIndex()
 	} else {
 		logger.Logrus().Errorln("caching: duplicate bucketName", bucketName)
 	}
@@ -117,11 +175,12 @@ func (c *CacheManager) Get(bucketName BucketName, key CacheKey, result any, keyA
 		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
 		return toolkitError.ErrBadBucketName
 	}
-	bs, err := bucket.GetBytes(key, keyAppend...)
+	bs, err := expireHard(bucket.GetBytes(key, keyAppend...))
+	c.recordGet(bucketName, err)
 	if err != nil {
 		return err
 	}
-	return c.codec.Decode(bs, result)
+	return c.decode(bucketName, key.RawKeyString(keyAppend...), bs, result)
 }
 
 // GetBytes 获取缓存字节数组
@@ -131,7 +190,9 @@ func (c *CacheManager) GetBytes(bucketName BucketName, key CacheKey, keyAppend .
 		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
 		return nil, toolkitError.ErrBadBucketName
 	}
-	return bucket.GetBytes(key, keyAppend...)
+	bs, err := expireHard(bucket.GetBytes(key, keyAppend...))
+	c.recordGet(bucketName, err)
+	return bs, err
 }
 
 // Put 缓存数据
@@ -141,11 +202,14 @@ func (c *CacheManager) Put(bucketName BucketName, key CacheKey, data any, keyApp
 		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
 		return toolkitError.ErrBadBucketName
 	}
-	bs, err := c.codec.Encode(data)
+	bs, err := c.encode(key.RawKeyString(keyAppend...), data)
 	if err != nil {
 		return err
 	}
-	return bucket.PutBytes(key, bs, keyAppend...)
+	c.negatives.delete(loadFlightKey(bucketName, key.RawKeyString(keyAppend...)))
+	err = bucket.PutBytes(key, bs, keyAppend...)
+	c.afterPut(bucketName, key.RawKeyString(keyAppend...), err)
+	return err
 }
 
 // PutBytes 缓存字节数组
@@ -155,7 +219,60 @@ func (c *CacheManager) PutBytes(bucketName BucketName, key CacheKey, data []byte
 		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
 		return toolkitError.ErrBadBucketName
 	}
-	return bucket.PutBytes(key, data, keyAppend...)
+	c.negatives.delete(loadFlightKey(bucketName, key.RawKeyString(keyAppend...)))
+	err := bucket.PutBytes(key, data, keyAppend...)
+	c.afterPut(bucketName, key.RawKeyString(keyAppend...), err)
+	return err
+}
+
+// PutWithTTL 缓存数据并指定过期时间，缓存桶需要实现 TTLCacheBucket
+func (c *CacheManager) PutWithTTL(bucketName BucketName, key CacheKey, data any, ttl time.Duration, keyAppend ...interface{}) error {
+	bucket, err := c.getTTLBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	bs, err := c.encode(key.RawKeyString(keyAppend...), data)
+	if err != nil {
+		return err
+	}
+	c.negatives.delete(loadFlightKey(bucketName, key.RawKeyString(keyAppend...)))
+	err = bucket.PutBytesWithTTL(key, bs, ttl, keyAppend...)
+	c.afterPut(bucketName, key.RawKeyString(keyAppend...), err)
+	return err
+}
+
+// PutBytesWithTTL 缓存字节数组并指定过期时间，缓存桶需要实现 TTLCacheBucket
+func (c *CacheManager) PutBytesWithTTL(bucketName BucketName, key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error {
+	bucket, err := c.getTTLBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	c.negatives.delete(loadFlightKey(bucketName, key.RawKeyString(keyAppend...)))
+	err = bucket.PutBytesWithTTL(key, data, ttl, keyAppend...)
+	c.afterPut(bucketName, key.RawKeyString(keyAppend...), err)
+	return err
+}
+
+// TTL 获取缓存剩余的过期时间，返回 0 表示未单独设置过期时间，缓存桶需要实现 TTLCacheBucket
+func (c *CacheManager) TTL(bucketName BucketName, key CacheKey, keyAppend ...interface{}) (time.Duration, error) {
+	bucket, err := c.getTTLBucket(bucketName)
+	if err != nil {
+		return 0, err
+	}
+	return bucket.TTL(key, keyAppend...)
+}
+
+func (c *CacheManager) getTTLBucket(bucketName BucketName) (TTLCacheBucket, error) {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return nil, toolkitError.ErrBadBucketName
+	}
+	ttlBucket, ok := bucket.(TTLCacheBucket)
+	if !ok {
+		return nil, toolkitError.ErrTTLNotSupported
+	}
+	return ttlBucket, nil
 }
 
 // Evict 清除缓存
@@ -165,5 +282,13 @@ func (c *CacheManager) Evict(bucketName BucketName, key CacheKey, keyAppend ...i
 		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
 		return toolkitError.ErrBadBucketName
 	}
-	return bucket.Evict(key, keyAppend...)
+	rawKey := key.RawKeyString(keyAppend...)
+	c.negatives.delete(loadFlightKey(bucketName, rawKey))
+	err := bucket.Evict(key, keyAppend...)
+	if err == nil {
+		c.untag(bucketName, rawKey)
+		c.publishQuietly(InvalidationEvent{Bucket: bucketName, Kind: InvalidationKeys, Keys: []string{rawKey}})
+	}
+	c.recordWrite(bucketName, metricEvict, err)
+	return err
 }
@@ -119,3 +119,43 @@ func TestCacheManagerCustomCodec(t *testing.T) {
 		t.Fatalf("unexpected custom codec value: got %+v, want %+v", got, want)
 	}
 }
+
+type plainBucket struct {
+	CacheBucket
+}
+
+func TestCacheManagerPutWithTTL(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("ttl:user:%d")
+	want := testUser{Name: "TTL", Sex: 1}
+
+	if err := manager.PutWithTTL(bucketName, key, want, 30*time.Millisecond, 1); err != nil {
+		t.Fatalf("put cache with ttl: %v", err)
+	}
+	ttl, err := manager.TTL(bucketName, key, 1)
+	if err != nil || ttl <= 0 {
+		t.Fatalf("expected positive ttl, got %v, %v", ttl, err)
+	}
+	var got testUser
+	if err = manager.Get(bucketName, key, &got, 1); err != nil || got != want {
+		t.Fatalf("expected cached value before expiry, got %+v, %v", got, err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if err = manager.Get(bucketName, key, &got, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after ttl, got %v", err)
+	}
+}
+
+func TestCacheManagerTTLNotSupported(t *testing.T) {
+	bucketName := NewBucketName("plain")
+	manager := NewCacheManager().AddBucket(bucketName, plainBucket{CacheBucket: newTestBigCache(t)})
+
+	err := manager.PutWithTTL(bucketName, NewCacheKey("key"), "value", time.Minute)
+	if !errors.Is(err, toolkitError.ErrTTLNotSupported) {
+		t.Fatalf("expected ErrTTLNotSupported, got %v", err)
+	}
+	if _, err = manager.TTL(bucketName, NewCacheKey("key")); !errors.Is(err, toolkitError.ErrTTLNotSupported) {
+		t.Fatalf("expected ErrTTLNotSupported, got %v", err)
+	}
+}
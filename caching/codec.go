@@ -0,0 +1,266 @@
+package caching
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/util/gob"
+	"github.com/acexy/golang-toolkit/util/json"
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// codecMagic 编码头的首字节
+// gob 数据的首字节为消息长度，取值只可能位于 0x00-0x7F 或 0xF8-0xFF，因此不会与编码头混淆
+const codecMagic byte = 0xCE
+
+const codecHeaderSize = 2
+
+const (
+	codecIDJSON    byte = 0x01
+	codecIDMsgpack byte = 0x02
+	codecIDGzip    byte = 0x10
+	codecIDZstd    byte = 0x11
+	// codecIDEncrypted 由 EncryptedCodec 加密的缓存值，编码头之后为 4 字节的密钥版本
+	codecIDEncrypted byte = 0x20
+	// codecIDFreshness 带有软过期和硬过期时间的缓存值，由读穿加载的软过期模式写入
+	codecIDFreshness byte = 0x30
+)
+
+const (
+	// CompressionGzip 使用 gzip 压缩
+	CompressionGzip CompressionAlgorithm = iota
+	// CompressionZstd 使用 zstd 压缩
+	CompressionZstd
+)
+
+// CompressionAlgorithm 表示缓存数据的压缩算法
+type CompressionAlgorithm uint8
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// withCodecHeader 在编码结果前添加编码头
+func withCodecHeader(id byte, body []byte) []byte {
+	bs := make([]byte, codecHeaderSize+len(body))
+	bs[0] = codecMagic
+	bs[1] = id
+	copy(bs[codecHeaderSize:], body)
+	return bs
+}
+
+// parseCodecHeader 解析编码头，无编码头时返回 false
+func parseCodecHeader(bs []byte) (byte, []byte, bool) {
+	if len(bs) < codecHeaderSize || bs[0] != codecMagic {
+		return 0, nil, false
+	}
+	return bs[1], bs[codecHeaderSize:], true
+}
+
+// decodeTagged 按编码头选择解码方式，使缓存桶在切换 Codec 后仍能读取旧数据
+// 无编码头的数据视为 GobCodec 写入的数据
+func decodeTagged(bs []byte, result any) error {
+	id, body, ok := parseCodecHeader(bs)
+	if !ok {
+		return gob.Decode(bs, result)
+	}
+	switch id {
+	case codecIDJSON:
+		return json.ParseBytesError(body, result)
+	case codecIDMsgpack:
+		return msgpack.Unmarshal(body, result)
+	case codecIDGzip, codecIDZstd:
+		raw, err := decompress(id, body)
+		if err != nil {
+			return err
+		}
+		return decodeTagged(raw, result)
+	case codecIDEncrypted:
+		// 加密的缓存值只能由配置了密钥的 EncryptedCodec 解码
+		return toolkitError.ErrEncryptionKeyNotFound
+	case codecIDFreshness:
+		raw, _, _, ok := parseFreshness(bs)
+		if !ok {
+			return toolkitError.ErrUnsupportedCodec
+		}
+		return decodeTagged(raw, result)
+	default:
+		return toolkitError.ErrUnsupportedCodec
+	}
+}
+
+// encodeWithKey 编码缓存值，codec 实现 KeyedCodec 时传入原始 key
+func encodeWithKey(codec Codec, rawKey string, data any) ([]byte, error) {
+	if keyed, ok := codec.(KeyedCodec); ok {
+		return keyed.EncodeWithKey(rawKey, data)
+	}
+	return codec.Encode(data)
+}
+
+// decodeWithKey 解码缓存值，codec 实现 KeyedCodec 时传入原始 key
+func decodeWithKey(codec Codec, rawKey string, bs []byte, result any) error {
+	if keyed, ok := codec.(KeyedCodec); ok {
+		return keyed.DecodeWithKey(rawKey, bs, result)
+	}
+	return codec.Decode(bs, result)
+}
+
+// encode 使用 Codec 编码原始 key 对应的缓存值
+func (c *CacheManager) encode(rawKey string, data any) ([]byte, error) {
+	return encodeWithKey(c.codec, rawKey, data)
+}
+
+// JSONCodec 使用 JSON 编码缓存值，便于其他语言读取和调试
+type JSONCodec struct {
+}
+
+func (j JSONCodec) Encode(data any) ([]byte, error) {
+	bs, err := json.ToBytesError(data)
+	if err != nil {
+		return nil, err
+	}
+	return withCodecHeader(codecIDJSON, bs), nil
+}
+
+func (j JSONCodec) Decode(bs []byte, result any) error {
+	return decodeTagged(bs, result)
+}
+
+// MsgpackCodec 使用 MessagePack 编码缓存值
+type MsgpackCodec struct {
+}
+
+func (m MsgpackCodec) Encode(data any) ([]byte, error) {
+	bs, err := msgpack.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return withCodecHeader(codecIDMsgpack, bs), nil
+}
+
+func (m MsgpackCodec) Decode(bs []byte, result any) error {
+	return decodeTagged(bs, result)
+}
+
+// CompressingCodec 包装其他 Codec，编码结果超过阈值时进行压缩
+type CompressingCodec struct {
+	codec     Codec
+	algorithm CompressionAlgorithm
+	threshold int
+}
+
+// NewCompressingCodec 创建压缩 Codec，codec 为空时使用 GobCodec，编码结果不小于 threshold 字节时压缩
+func NewCompressingCodec(codec Codec, algorithm CompressionAlgorithm, threshold int) (*CompressingCodec, error) {
+	if algorithm != CompressionGzip && algorithm != CompressionZstd {
+		return nil, toolkitError.ErrUnsupportedCompression
+	}
+	return &CompressingCodec{
+		codec:     defaultCodec(codec),
+		algorithm: algorithm,
+		threshold: threshold,
+	}, nil
+}
+
+func (c *CompressingCodec) Encode(data any) ([]byte, error) {
+	return c.EncodeWithKey("", data)
+}
+
+func (c *CompressingCodec) Decode(bs []byte, result any) error {
+	return c.DecodeWithKey("", bs, result)
+}
+
+// EncodeWithKey 编码并压缩缓存值，被包装的 Codec 实现 KeyedCodec 时传入原始 key
+func (c *CompressingCodec) EncodeWithKey(rawKey string, data any) ([]byte, error) {
+	bs, err := encodeWithKey(c.codec, rawKey, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) < c.threshold {
+		return bs, nil
+	}
+	id := codecIDGzip
+	if c.algorithm == CompressionZstd {
+		id = codecIDZstd
+	}
+	compressed, err := compress(id, bs)
+	if err != nil {
+		return nil, err
+	}
+	// 压缩无收益时保留原始编码结果
+	if len(compressed)+codecHeaderSize >= len(bs) {
+		return bs, nil
+	}
+	return withCodecHeader(id, compressed), nil
+}
+
+// DecodeWithKey 解压并解码缓存值，被包装的 Codec 实现 KeyedCodec 时传入原始 key
+func (c *CompressingCodec) DecodeWithKey(rawKey string, bs []byte, result any) error {
+	id, body, ok := parseCodecHeader(bs)
+	if ok && (id == codecIDGzip || id == codecIDZstd) {
+		raw, err := decompress(id, body)
+		if err != nil {
+			return err
+		}
+		return decodeWithKey(c.codec, rawKey, raw, result)
+	}
+	return decodeWithKey(c.codec, rawKey, bs, result)
+}
+
+func loadZstd() error {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdErr
+}
+
+func compress(id byte, bs []byte) ([]byte, error) {
+	switch id {
+	case codecIDGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(bs); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case codecIDZstd:
+		if err := loadZstd(); err != nil {
+			return nil, err
+		}
+		return zstdEncoder.EncodeAll(bs, nil), nil
+	default:
+		return nil, toolkitError.ErrUnsupportedCompression
+	}
+}
+
+func decompress(id byte, bs []byte) ([]byte, error) {
+	switch id {
+	case codecIDGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(bs))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = reader.Close() }()
+		return io.ReadAll(reader)
+	case codecIDZstd:
+		if err := loadZstd(); err != nil {
+			return nil, err
+		}
+		return zstdDecoder.DecodeAll(bs, nil)
+	default:
+		return nil, toolkitError.ErrUnsupportedCompression
+	}
+}
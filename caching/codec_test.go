@@ -0,0 +1,117 @@
+package caching
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+type codecTestValue struct {
+	Name  string
+	Items []int
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	gzipCodec, err := NewCompressingCodec(JSONCodec{}, CompressionGzip, 0)
+	if err != nil {
+		t.Fatalf("new gzip codec: %v", err)
+	}
+	zstdCodec, err := NewCompressingCodec(MsgpackCodec{}, CompressionZstd, 0)
+	if err != nil {
+		t.Fatalf("new zstd codec: %v", err)
+	}
+	want := codecTestValue{Name: strings.Repeat("cache", 50), Items: []int{1, 2, 3}}
+
+	for name, codec := range map[string]Codec{
+		"gob":     GobCodec{},
+		"json":    JSONCodec{},
+		"msgpack": MsgpackCodec{},
+		"gzip":    gzipCodec,
+		"zstd":    zstdCodec,
+	} {
+		bs, err := codec.Encode(want)
+		if err != nil {
+			t.Fatalf("%s encode: %v", name, err)
+		}
+		var got codecTestValue
+		if err = codec.Decode(bs, &got); err != nil {
+			t.Fatalf("%s decode: %v", name, err)
+		}
+		if got.Name != want.Name || len(got.Items) != 3 || got.Items[2] != 3 {
+			t.Fatalf("%s unexpected value %+v", name, got)
+		}
+	}
+}
+
+func TestJSONCodecIsReadable(t *testing.T) {
+	bs, err := JSONCodec{}.Encode(codecTestValue{Name: "json"})
+	if err != nil {
+		t.Fatalf("json encode: %v", err)
+	}
+	if !bytes.Contains(bs, []byte(`"Name":"json"`)) {
+		t.Fatalf("expected readable json payload, got %q", bs)
+	}
+}
+
+func TestCodecMigrationWithoutFlush(t *testing.T) {
+	gzipCodec, _ := NewCompressingCodec(JSONCodec{}, CompressionGzip, 0)
+	want := codecTestValue{Name: strings.Repeat("migrate", 20)}
+	writers := map[string]Codec{"gob": GobCodec{}, "json": JSONCodec{}, "msgpack": MsgpackCodec{}, "gzip": gzipCodec}
+	readers := map[string]Codec{"gob": GobCodec{}, "json": JSONCodec{}, "msgpack": MsgpackCodec{}, "gzip": gzipCodec}
+
+	for writerName, writer := range writers {
+		bs, err := writer.Encode(want)
+		if err != nil {
+			t.Fatalf("%s encode: %v", writerName, err)
+		}
+		for readerName, reader := range readers {
+			var got codecTestValue
+			if err = reader.Decode(bs, &got); err != nil || got.Name != want.Name {
+				t.Fatalf("%s reading %s data: %+v, %v", readerName, writerName, got, err)
+			}
+		}
+	}
+}
+
+func TestCompressingCodecThreshold(t *testing.T) {
+	codec, err := NewCompressingCodec(JSONCodec{}, CompressionZstd, 128)
+	if err != nil {
+		t.Fatalf("new compressing codec: %v", err)
+	}
+
+	small, _ := codec.Encode("small")
+	if id, _, _ := parseCodecHeader(small); id != codecIDJSON {
+		t.Fatalf("expected small payload to stay uncompressed, got codec id %#x", id)
+	}
+	large, _ := codec.Encode(strings.Repeat("large", 100))
+	if id, _, _ := parseCodecHeader(large); id != codecIDZstd {
+		t.Fatalf("expected large payload to be compressed, got codec id %#x", id)
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	if _, err := NewCompressingCodec(nil, CompressionAlgorithm(9), 0); !errors.Is(err, toolkitError.ErrUnsupportedCompression) {
+		t.Fatalf("expected ErrUnsupportedCompression, got %v", err)
+	}
+	var got string
+	if err := (JSONCodec{}).Decode([]byte{codecMagic, 0x7F, 'x'}, &got); !errors.Is(err, toolkitError.ErrUnsupportedCodec) {
+		t.Fatalf("expected ErrUnsupportedCodec, got %v", err)
+	}
+}
+
+func TestCacheManagerWithJSONCodec(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t, JSONCodec{})
+	key := NewCacheKey("codec:%d")
+	want := testUser{Name: "Json", Sex: 1}
+
+	if err := manager.Put(bucketName, key, want, 1); err != nil {
+		t.Fatalf("put cache: %v", err)
+	}
+	var got testUser
+	if err := manager.Get(bucketName, key, &got, 1); err != nil || got != want {
+		t.Fatalf("unexpected cache value %+v, %v", got, err)
+	}
+}
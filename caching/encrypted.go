@@ -0,0 +1,125 @@
+package caching
+
+import (
+	"encoding/binary"
+
+	"github.com/acexy/golang-toolkit/crypto/symmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// encryptedHeaderSize 编码头之后为 4 字节的密钥版本
+const encryptedHeaderSize = codecHeaderSize + 4
+
+// EncryptionKey 表示 EncryptedCodec 使用的一个密钥版本
+type EncryptionKey struct {
+	// Version 密钥版本，写入加密结果中用于解密时选择密钥
+	Version uint32
+	// Key AES 密钥，长度为 16、24 或 32 字节
+	Key []byte
+}
+
+// EncryptedCodecOption 表示创建 EncryptedCodec 时使用的配置
+type EncryptedCodecOption struct {
+	// Codec 被包装的 Codec，为空时使用 GobCodec
+	Codec Codec
+	// Keys 密钥版本，至少需要一个，版本号相同的密钥以后出现的为准
+	Keys []EncryptionKey
+	// AllowPlaintext 是否允许读取未加密的缓存值，仅用于从未加密缓存迁移的过渡期
+	// 开启后任何能写入缓存的一方都可以写入未经认证的值，迁移完成后应关闭
+	AllowPlaintext bool
+}
+
+// EncryptedCodec 包装其他 Codec，使用 AES-GCM 加密编码结果，适合缓存包含敏感信息的数据
+// 通过 CacheManager 读写时缓存值与原始 key 绑定，复制到其他 key 下的密文无法被解密
+// 支持密钥轮换：使用版本号最大的密钥加密，使用任意已配置的密钥版本解密
+// 默认拒绝读取未加密的缓存值
+type EncryptedCodec struct {
+	codec          Codec
+	keys           map[uint32]*symmetric.AESEncrypt
+	current        uint32
+	allowPlaintext bool
+}
+
+// NewEncryptedCodec 创建加密 Codec，codec 为空时使用 GobCodec，版本号相同的密钥以后出现的为准
+func NewEncryptedCodec(codec Codec, keys ...EncryptionKey) (*EncryptedCodec, error) {
+	return NewEncryptedCodecWithOption(EncryptedCodecOption{Codec: codec, Keys: keys})
+}
+
+// NewEncryptedCodecWithOption 使用指定配置创建加密 Codec
+func NewEncryptedCodecWithOption(option EncryptedCodecOption) (*EncryptedCodec, error) {
+	if len(option.Keys) == 0 {
+		return nil, toolkitError.ErrEncryptionKeyNotFound
+	}
+	encrypted := &EncryptedCodec{
+		codec:          defaultCodec(option.Codec),
+		keys:           make(map[uint32]*symmetric.AESEncrypt, len(option.Keys)),
+		allowPlaintext: option.AllowPlaintext,
+	}
+	for _, key := range option.Keys {
+		crypt, err := symmetric.NewAESWithOption(key.Key, symmetric.AESOption{Mode: symmetric.AESModeGCM})
+		if err != nil {
+			return nil, err
+		}
+		encrypted.keys[key.Version] = crypt
+		if key.Version > encrypted.current {
+			encrypted.current = key.Version
+		}
+	}
+	return encrypted, nil
+}
+
+// CurrentVersion 获取加密使用的密钥版本
+func (e *EncryptedCodec) CurrentVersion() uint32 {
+	return e.current
+}
+
+func (e *EncryptedCodec) Encode(data any) ([]byte, error) {
+	return e.EncodeWithKey("", data)
+}
+
+func (e *EncryptedCodec) Decode(bs []byte, result any) error {
+	return e.DecodeWithKey("", bs, result)
+}
+
+// EncodeWithKey 编码并加密缓存值，原始 key 作为 GCM 附加认证数据与密文绑定
+func (e *EncryptedCodec) EncodeWithKey(rawKey string, data any) ([]byte, error) {
+	body, err := encodeWithKey(e.codec, rawKey, data)
+	if err != nil {
+		return nil, err
+	}
+	cipherData, err := e.keys[e.current].EncryptWithAAD(body, []byte(rawKey))
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]byte, encryptedHeaderSize+len(cipherData))
+	bs[0] = codecMagic
+	bs[1] = codecIDEncrypted
+	binary.BigEndian.PutUint32(bs[codecHeaderSize:], e.current)
+	copy(bs[encryptedHeaderSize:], cipherData)
+	return bs, nil
+}
+
+// DecodeWithKey 解密并解码缓存值，密文不属于 rawKey 时返回 ErrAADMismatch，
+// 未加密的缓存值返回 ErrCacheValueNotEncrypted，开启 AllowPlaintext 时直接交给被包装的 Codec 解码
+func (e *EncryptedCodec) DecodeWithKey(rawKey string, bs []byte, result any) error {
+	id, _, ok := parseCodecHeader(bs)
+	if !ok || id != codecIDEncrypted {
+		if e.allowPlaintext {
+			return decodeWithKey(e.codec, rawKey, bs, result)
+		}
+		return toolkitError.ErrCacheValueNotEncrypted
+	}
+	if len(bs) < encryptedHeaderSize {
+		return toolkitError.ErrCipherDataTooShort
+	}
+	crypt, ok := e.keys[binary.BigEndian.Uint32(bs[codecHeaderSize:])]
+	if !ok {
+		return toolkitError.ErrEncryptionKeyNotFound
+	}
+	plain, err := crypt.DecryptWithAAD(bs[encryptedHeaderSize:], []byte(rawKey))
+	if err != nil {
+		return err
+	}
+	return decodeWithKey(e.codec, rawKey, plain, result)
+}
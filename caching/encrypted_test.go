@@ -0,0 +1,141 @@
+package caching
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func testEncryptionKey(version uint32) EncryptionKey {
+	return EncryptionKey{Version: version, Key: bytes.Repeat([]byte{byte(version)}, 32)}
+}
+
+func TestEncryptedCodecWithCacheManager(t *testing.T) {
+	codec, err := NewEncryptedCodec(MsgpackCodec{}, testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("new encrypted codec: %v", err)
+	}
+	manager, bucketName := newTestCacheManager(t, codec)
+	key := NewCacheKey("pii:%d")
+	want := testUser{Name: "Secret Name", Sex: 1}
+
+	if err = manager.Put(bucketName, key, want, 1); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	stored, err := manager.GetBytes(bucketName, key, 1)
+	if err != nil {
+		t.Fatalf("get bytes: %v", err)
+	}
+	if bytes.Contains(stored, []byte(want.Name)) {
+		t.Fatal("expected cached bytes to be encrypted")
+	}
+	// key 只作为附加认证数据参与认证，不会写入密文
+	if bytes.Contains(stored, []byte(key.RawKeyString(1))) {
+		t.Fatal("expected cache key not to be stored in cipher data")
+	}
+	var got testUser
+	if err = manager.Get(bucketName, key, &got, 1); err != nil || got != want {
+		t.Fatalf("unexpected decrypted value %+v, %v", got, err)
+	}
+
+	// 复制到其他 key 下的密文无法解密
+	if err = manager.PutBytes(bucketName, key, stored, 2); err != nil {
+		t.Fatalf("put copied bytes: %v", err)
+	}
+	if err = manager.Get(bucketName, key, &got, 2); !errors.Is(err, toolkitError.ErrAADMismatch) {
+		t.Fatalf("expected ErrAADMismatch, got %v", err)
+	}
+}
+
+func TestEncryptedCodecKeyRotation(t *testing.T) {
+	oldCodec, err := NewEncryptedCodec(nil, testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("new old codec: %v", err)
+	}
+	oldData, err := oldCodec.EncodeWithKey("user:1", testUser{Name: "old"})
+	if err != nil {
+		t.Fatalf("encode with old key: %v", err)
+	}
+
+	rotated, err := NewEncryptedCodec(nil, testEncryptionKey(2), testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("new rotated codec: %v", err)
+	}
+	if rotated.CurrentVersion() != 2 {
+		t.Fatalf("expected newest key version, got %d", rotated.CurrentVersion())
+	}
+	var got testUser
+	if err = rotated.DecodeWithKey("user:1", oldData, &got); err != nil || got.Name != "old" {
+		t.Fatalf("expected old data to decrypt after rotation, got %+v, %v", got, err)
+	}
+	newData, err := rotated.EncodeWithKey("user:1", testUser{Name: "new"})
+	if err != nil {
+		t.Fatalf("encode with new key: %v", err)
+	}
+	if err = oldCodec.DecodeWithKey("user:1", newData, &got); !errors.Is(err, toolkitError.ErrEncryptionKeyNotFound) {
+		t.Fatalf("expected ErrEncryptionKeyNotFound, got %v", err)
+	}
+}
+
+func TestEncryptedCodecComposition(t *testing.T) {
+	encrypted, err := NewEncryptedCodec(JSONCodec{}, testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("new encrypted codec: %v", err)
+	}
+	// 先压缩后加密，CompressingCodec 将原始 key 传递给 EncryptedCodec
+	compressing, err := NewCompressingCodec(encrypted, CompressionGzip, 0)
+	if err != nil {
+		t.Fatalf("new compressing codec: %v", err)
+	}
+	manager, bucketName := newTestCacheManager(t, compressing)
+	key := NewCacheKey("composed")
+	want := testUser{Name: string(bytes.Repeat([]byte("a"), 256))}
+
+	if err = manager.Put(bucketName, key, want); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	var got testUser
+	if err = manager.Get(bucketName, key, &got); err != nil || got != want {
+		t.Fatalf("unexpected composed value, %v", err)
+	}
+
+	if _, err = NewEncryptedCodec(nil); !errors.Is(err, toolkitError.ErrEncryptionKeyNotFound) {
+		t.Fatalf("expected ErrEncryptionKeyNotFound without keys, got %v", err)
+	}
+	if _, err = NewEncryptedCodec(nil, EncryptionKey{Version: 1, Key: []byte("short")}); !errors.Is(err, toolkitError.ErrInvalidAESKeySize) {
+		t.Fatalf("expected ErrInvalidAESKeySize, got %v", err)
+	}
+}
+
+func TestEncryptedCodecRejectsPlaintext(t *testing.T) {
+	codec, err := NewEncryptedCodec(JSONCodec{}, testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("new encrypted codec: %v", err)
+	}
+	plain, err := JSONCodec{}.Encode(testUser{Name: "planted"})
+	if err != nil {
+		t.Fatalf("encode plain: %v", err)
+	}
+	var got testUser
+	if err = codec.DecodeWithKey("user:1", plain, &got); !errors.Is(err, toolkitError.ErrCacheValueNotEncrypted) {
+		t.Fatalf("expected ErrCacheValueNotEncrypted, got %v", err)
+	}
+	if err = codec.Decode([]byte{0x01, 0x02}, &got); !errors.Is(err, toolkitError.ErrCacheValueNotEncrypted) {
+		t.Fatalf("expected ErrCacheValueNotEncrypted for gob bytes, got %v", err)
+	}
+
+	// 迁移期间显式开启后可以读取未加密的旧数据
+	migrating, err := NewEncryptedCodecWithOption(EncryptedCodecOption{
+		Codec:          JSONCodec{},
+		Keys:           []EncryptionKey{testEncryptionKey(1)},
+		AllowPlaintext: true,
+	})
+	if err != nil {
+		t.Fatalf("new migrating codec: %v", err)
+	}
+	if err = migrating.DecodeWithKey("user:1", plain, &got); err != nil || got.Name != "planted" {
+		t.Fatalf("expected plaintext to decode when allowed, got %+v, %v", got, err)
+	}
+}
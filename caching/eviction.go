@@ -0,0 +1,267 @@
+package caching
+
+import (
+	"container/list"
+)
+
+const (
+	// EvictionPolicyLRU 淘汰最久未被访问的缓存项
+	EvictionPolicyLRU EvictionPolicy = iota
+	// EvictionPolicyLFU 淘汰访问次数最少的缓存项，次数相同时淘汰最久未被访问的缓存项
+	EvictionPolicyLFU
+	// EvictionPolicyARC 自适应替换缓存，根据访问模式在最近访问和频繁访问之间自动调整
+	EvictionPolicyARC
+)
+
+// EvictionPolicy 表示内存缓存桶的淘汰策略
+type EvictionPolicy uint8
+
+// evictionPolicy 记录缓存项的访问情况并选出需要淘汰的 key，调用方负责加锁
+type evictionPolicy interface {
+	// add 记录新写入的 key
+	add(key string)
+	// touch 记录 key 被访问或更新
+	touch(key string)
+	// remove 移除 key，不参与淘汰统计
+	remove(key string)
+	// evict 选出并移除下一个需要淘汰的 key
+	evict() (string, bool)
+}
+
+func newEvictionPolicy(policy EvictionPolicy, capacity int) (evictionPolicy, bool) {
+	switch policy {
+	case EvictionPolicyLRU:
+		return newLruList(), true
+	case EvictionPolicyLFU:
+		return newLfuPolicy(), true
+	case EvictionPolicyARC:
+		return newArcPolicy(capacity), true
+	default:
+		return nil, false
+	}
+}
+
+// lruList 按访问顺序排列的 key 列表，队首为最近访问
+type lruList struct {
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+func newLruList() *lruList {
+	return &lruList{
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+	}
+}
+
+func (l *lruList) len() int {
+	return l.order.Len()
+}
+
+func (l *lruList) contains(key string) bool {
+	_, ok := l.elements[key]
+	return ok
+}
+
+func (l *lruList) add(key string) {
+	if element, ok := l.elements[key]; ok {
+		l.order.MoveToFront(element)
+		return
+	}
+	l.elements[key] = l.order.PushFront(key)
+}
+
+func (l *lruList) touch(key string) {
+	if element, ok := l.elements[key]; ok {
+		l.order.MoveToFront(element)
+	}
+}
+
+func (l *lruList) remove(key string) {
+	if element, ok := l.elements[key]; ok {
+		l.order.Remove(element)
+		delete(l.elements, key)
+	}
+}
+
+func (l *lruList) evict() (string, bool) {
+	element := l.order.Back()
+	if element == nil {
+		return "", false
+	}
+	key := element.Value.(string)
+	l.order.Remove(element)
+	delete(l.elements, key)
+	return key, true
+}
+
+type lfuNode struct {
+	freq    int
+	element *list.Element
+}
+
+// lfuPolicy 按访问次数分组，每组内部按访问顺序排列
+type lfuPolicy struct {
+	nodes   map[string]*lfuNode
+	freqs   map[int]*list.List
+	minFreq int
+}
+
+func newLfuPolicy() *lfuPolicy {
+	return &lfuPolicy{
+		nodes: make(map[string]*lfuNode),
+		freqs: make(map[int]*list.List),
+	}
+}
+
+func (l *lfuPolicy) push(key string, freq int) *list.Element {
+	keys, ok := l.freqs[freq]
+	if !ok {
+		keys = list.New()
+		l.freqs[freq] = keys
+	}
+	return keys.PushFront(key)
+}
+
+func (l *lfuPolicy) unlink(node *lfuNode) {
+	keys := l.freqs[node.freq]
+	keys.Remove(node.element)
+	if keys.Len() == 0 {
+		delete(l.freqs, node.freq)
+	}
+}
+
+func (l *lfuPolicy) add(key string) {
+	if _, ok := l.nodes[key]; ok {
+		l.touch(key)
+		return
+	}
+	l.nodes[key] = &lfuNode{freq: 1, element: l.push(key, 1)}
+	l.minFreq = 1
+}
+
+func (l *lfuPolicy) touch(key string) {
+	node, ok := l.nodes[key]
+	if !ok {
+		return
+	}
+	l.unlink(node)
+	if node.freq == l.minFreq {
+		if _, exists := l.freqs[node.freq]; !exists {
+			l.minFreq++
+		}
+	}
+	node.freq++
+	node.element = l.push(key, node.freq)
+}
+
+func (l *lfuPolicy) remove(key string) {
+	node, ok := l.nodes[key]
+	if !ok {
+		return
+	}
+	l.unlink(node)
+	delete(l.nodes, key)
+}
+
+func (l *lfuPolicy) evict() (string, bool) {
+	if len(l.nodes) == 0 {
+		return "", false
+	}
+	keys, ok := l.freqs[l.minFreq]
+	if !ok {
+		// remove 可能移除了最小访问次数分组，重新查找
+		l.minFreq = 0
+		for freq := range l.freqs {
+			if l.minFreq == 0 || freq < l.minFreq {
+				l.minFreq = freq
+			}
+		}
+		keys = l.freqs[l.minFreq]
+	}
+	key := keys.Back().Value.(string)
+	l.remove(key)
+	return key, true
+}
+
+// arcPolicy 自适应替换缓存
+// t1/t2 为仍在缓存中的最近访问/频繁访问 key，b1/b2 为对应的已淘汰 key 记录，用于调整 t1 的目标大小 p
+type arcPolicy struct {
+	capacity int
+	p        int
+	t1       *lruList
+	t2       *lruList
+	b1       *lruList
+	b2       *lruList
+}
+
+// newArcPolicy 创建 ARC 策略，capacity 为 0 时使用当前缓存项数量作为容量
+func newArcPolicy(capacity int) *arcPolicy {
+	return &arcPolicy{
+		capacity: capacity,
+		t1:       newLruList(),
+		t2:       newLruList(),
+		b1:       newLruList(),
+		b2:       newLruList(),
+	}
+}
+
+func (a *arcPolicy) size() int {
+	if a.capacity > 0 {
+		return a.capacity
+	}
+	return max(a.t1.len()+a.t2.len(), 1)
+}
+
+func (a *arcPolicy) add(key string) {
+	c := a.size()
+	switch {
+	case a.t1.contains(key) || a.t2.contains(key):
+		a.touch(key)
+		return
+	case a.b1.contains(key):
+		a.p = min(c, a.p+max(a.b2.len()/max(a.b1.len(), 1), 1))
+		a.b1.remove(key)
+		a.t2.add(key)
+	case a.b2.contains(key):
+		a.p = max(0, a.p-max(a.b1.len()/max(a.b2.len(), 1), 1))
+		a.b2.remove(key)
+		a.t2.add(key)
+	default:
+		a.t1.add(key)
+	}
+	for a.t1.len()+a.b1.len() > c && a.b1.len() > 0 {
+		a.b1.evict()
+	}
+	for a.t1.len()+a.t2.len()+a.b1.len()+a.b2.len() > 2*c && a.b2.len() > 0 {
+		a.b2.evict()
+	}
+}
+
+func (a *arcPolicy) touch(key string) {
+	if a.t1.contains(key) {
+		a.t1.remove(key)
+		a.t2.add(key)
+		return
+	}
+	a.t2.touch(key)
+}
+
+func (a *arcPolicy) remove(key string) {
+	a.t1.remove(key)
+	a.t2.remove(key)
+}
+
+func (a *arcPolicy) evict() (string, bool) {
+	if a.t1.len() > 0 && (a.t1.len() > a.p || a.t2.len() == 0) {
+		key, _ := a.t1.evict()
+		a.b1.add(key)
+		return key, true
+	}
+	if a.t2.len() > 0 {
+		key, _ := a.t2.evict()
+		a.b2.add(key)
+		return key, true
+	}
+	return "", false
+}
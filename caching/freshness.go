@@ -0,0 +1,69 @@
+package caching
+
+import (
+	"encoding/binary"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// freshnessHeaderSize 编码头之后依次为软过期和硬过期时间的 UnixNano，0 表示不过期
+const freshnessHeaderSize = codecHeaderSize + 16
+
+const (
+	entryFresh entryFreshness = iota
+	entryStale
+	entryExpired
+)
+
+// entryFreshness 表示缓存值相对于软过期和硬过期时间的状态
+type entryFreshness uint8
+
+// withFreshness 为编码结果添加软过期和硬过期时间
+func withFreshness(body []byte, now time.Time, softTTL, hardTTL time.Duration) []byte {
+	bs := make([]byte, freshnessHeaderSize+len(body))
+	bs[0] = codecMagic
+	bs[1] = codecIDFreshness
+	binary.BigEndian.PutUint64(bs[codecHeaderSize:], uint64(now.Add(softTTL).UnixNano()))
+	if hardTTL > 0 {
+		binary.BigEndian.PutUint64(bs[codecHeaderSize+8:], uint64(now.Add(hardTTL).UnixNano()))
+	}
+	copy(bs[freshnessHeaderSize:], body)
+	return bs
+}
+
+// parseFreshness 解析软过期和硬过期时间，不是软过期模式写入的缓存值时返回 false
+func parseFreshness(bs []byte) ([]byte, int64, int64, bool) {
+	id, _, ok := parseCodecHeader(bs)
+	if !ok || id != codecIDFreshness || len(bs) < freshnessHeaderSize {
+		return nil, 0, 0, false
+	}
+	softExpireAt := int64(binary.BigEndian.Uint64(bs[codecHeaderSize:]))
+	hardExpireAt := int64(binary.BigEndian.Uint64(bs[codecHeaderSize+8:]))
+	return bs[freshnessHeaderSize:], softExpireAt, hardExpireAt, true
+}
+
+// expireHard 超过硬过期时间的缓存值视为未命中，使直接读取与读穿加载的结果一致
+func expireHard(bs []byte, err error) ([]byte, error) {
+	if err == nil && freshnessOf(bs, time.Now()) == entryExpired {
+		return nil, toolkitError.ErrCacheMiss
+	}
+	return bs, err
+}
+
+// freshnessOf 获取缓存值的过期状态，未带过期时间头的缓存值始终视为新鲜
+func freshnessOf(bs []byte, now time.Time) entryFreshness {
+	_, softExpireAt, hardExpireAt, ok := parseFreshness(bs)
+	if !ok {
+		return entryFresh
+	}
+	nanos := now.UnixNano()
+	switch {
+	case hardExpireAt != 0 && nanos >= hardExpireAt:
+		return entryExpired
+	case softExpireAt != 0 && nanos >= softExpireAt:
+		return entryStale
+	default:
+		return entryFresh
+	}
+}
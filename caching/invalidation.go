@@ -0,0 +1,258 @@
+package caching
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+	"github.com/acexy/golang-toolkit/util/json"
+	"github.com/google/uuid"
+)
+
+const (
+	// InvalidationKeys 清除指定的原始 key
+	InvalidationKeys InvalidationKind = "keys"
+	// InvalidationTag 清除标签下的所有 key
+	InvalidationTag InvalidationKind = "tag"
+	// InvalidationPrefix 清除指定前缀的所有 key
+	InvalidationPrefix InvalidationKind = "prefix"
+)
+
+// invalidationMaxKeys 单个失效通知包含的最大 key 数量，超过时拆分为多个通知
+const invalidationMaxKeys = 64
+
+// InvalidationKind 表示失效通知的类型
+type InvalidationKind string
+
+// InvalidationEvent 在节点之间传播的缓存失效通知
+type InvalidationEvent struct {
+	// NodeID 发出通知的节点，节点会忽略自己发出的通知
+	NodeID string `json:"nodeId"`
+	// Bucket 缓存桶名称
+	Bucket BucketName `json:"bucket"`
+	// Kind 通知类型
+	Kind InvalidationKind `json:"kind"`
+	// Keys 需要清除的原始 key，Kind 为 InvalidationKeys 时有效
+	Keys []string `json:"keys,omitempty"`
+	// Tag 需要清除的标签，Kind 为 InvalidationTag 时有效
+	Tag string `json:"tag,omitempty"`
+	// Prefix 需要清除的 key 前缀，Kind 为 InvalidationPrefix 时有效
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// InvalidationTransport 失效通知的传输方式，负责在节点之间广播已编码的通知
+type InvalidationTransport interface {
+
+	// Publish 向其他节点广播通知
+	Publish(payload []byte) error
+
+	// Subscribe 注册接收通知的回调，回调可能在传输内部的协程中执行
+	Subscribe(handler func(payload []byte)) error
+
+	// Close 关闭传输
+	Close() error
+}
+
+// LocalEvictor 区分本地与共享存储的缓存桶扩展，收到其他节点的通知时只清除本地部分
+type LocalEvictor interface {
+
+	// EvictLocal 仅清除本地缓存
+	EvictLocal(key CacheKey, keyAppend ...interface{}) error
+}
+
+type invalidationBus struct {
+	nodeID    string
+	transport InvalidationTransport
+}
+
+// EnableInvalidation 使用指定的传输方式开启失效通知
+// 开启后 Evict、EvictMany、EvictByTag 和 EvictByPrefix 成功时会通知其他节点清除相同的缓存
+func (c *CacheManager) EnableInvalidation(transport InvalidationTransport) error {
+	c.lock.Lock()
+	if c.bus != nil {
+		c.lock.Unlock()
+		return toolkitError.ErrInvalidationEnabled
+	}
+	bus := &invalidationBus{nodeID: uuid.NewString(), transport: transport}
+	c.bus = bus
+	c.lock.Unlock()
+
+	if err := transport.Subscribe(c.handleInvalidation); err != nil {
+		c.lock.Lock()
+		c.bus = nil
+		c.lock.Unlock()
+		return err
+	}
+	return nil
+}
+
+// NodeID 获取当前节点在失效通知中的标识，未开启失效通知时返回空字符串
+func (c *CacheManager) NodeID() string {
+	if bus := c.invalidationBus(); bus != nil {
+		return bus.nodeID
+	}
+	return ""
+}
+
+// PublishInvalidation 通知其他节点清除指定的原始 key，不影响当前节点
+// 可在 TieredBucketOption.OnInvalidate 中调用，使写入共享缓存后其他节点的本地缓存失效
+func (c *CacheManager) PublishInvalidation(bucketName BucketName, rawKeys ...string) error {
+	for start := 0; start < len(rawKeys); start += invalidationMaxKeys {
+		end := min(start+invalidationMaxKeys, len(rawKeys))
+		err := c.publish(InvalidationEvent{Bucket: bucketName, Kind: InvalidationKeys, Keys: rawKeys[start:end]})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CacheManager) invalidationBus() *invalidationBus {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.bus
+}
+
+// publish 发布失效通知，未开启失效通知时忽略
+func (c *CacheManager) publish(event InvalidationEvent) error {
+	bus := c.invalidationBus()
+	if bus == nil {
+		return nil
+	}
+	event.NodeID = bus.nodeID
+	payload, err := json.ToBytesError(event)
+	if err != nil {
+		return err
+	}
+	return bus.transport.Publish(payload)
+}
+
+// publishQuietly 发布失效通知，失败时只记录日志，本地清除的结果不受影响
+func (c *CacheManager) publishQuietly(event InvalidationEvent) {
+	var err error
+	if event.Kind == InvalidationKeys {
+		err = c.PublishInvalidation(event.Bucket, event.Keys...)
+	} else {
+		err = c.publish(event)
+	}
+	if err != nil {
+		logger.Logrus().Errorln("caching: publish invalidation failed", event.Bucket, err)
+	}
+}
+
+func (c *CacheManager) handleInvalidation(payload []byte) {
+	var event InvalidationEvent
+	if err := json.ParseBytesError(payload, &event); err != nil {
+		logger.Logrus().Errorln("caching: bad invalidation event", err)
+		return
+	}
+	if bus := c.invalidationBus(); bus == nil || event.NodeID == bus.nodeID {
+		return
+	}
+	if err := c.applyInvalidation(event); err != nil {
+		logger.Logrus().Errorln("caching: apply invalidation failed", event.Bucket, err)
+	}
+}
+
+// applyInvalidation 在当前节点执行其他节点发出的失效通知，不会再次广播
+func (c *CacheManager) applyInvalidation(event InvalidationEvent) error {
+	bucket := c.GetBucket(event.Bucket)
+	if bucket == nil {
+		// 当前节点没有该缓存桶
+		return nil
+	}
+	var rawKeys []string
+	switch event.Kind {
+	case InvalidationKeys:
+		rawKeys = event.Keys
+	case InvalidationTag:
+		rawKeys = c.tagIndex(event.Bucket).keysOf(event.Tag)
+	case InvalidationPrefix:
+		iterable, ok := bucket.(IterableCacheBucket)
+		if !ok {
+			return nil
+		}
+		err := iterable.Range(func(rawKey string, _ []byte, _ time.Duration) bool {
+			if strings.HasPrefix(rawKey, event.Prefix) {
+				rawKeys = append(rawKeys, rawKey)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	localEvictor, local := bucket.(LocalEvictor)
+	for _, rawKey := range rawKeys {
+		c.negatives.delete(loadFlightKey(event.Bucket, rawKey))
+		var err error
+		if local {
+			err = localEvictor.EvictLocal(NewCacheKey(rawKey))
+		} else {
+			err = bucket.Evict(NewCacheKey(rawKey))
+		}
+		// 本节点可能从未缓存过该 key
+		if err != nil && !isEntryNotFound(err) {
+			return err
+		}
+		c.untag(event.Bucket, rawKey)
+	}
+	return nil
+}
+
+// MemoryInvalidationHub 进程内的失效通知中心，用于测试或同一进程中的多个 CacheManager
+type MemoryInvalidationHub struct {
+	lock     sync.RWMutex
+	handlers map[*memoryInvalidationTransport]func(payload []byte)
+}
+
+type memoryInvalidationTransport struct {
+	hub *MemoryInvalidationHub
+}
+
+// NewMemoryInvalidationHub 创建进程内的失效通知中心
+func NewMemoryInvalidationHub() *MemoryInvalidationHub {
+	return &MemoryInvalidationHub{handlers: make(map[*memoryInvalidationTransport]func(payload []byte))}
+}
+
+// Transport 创建一个连接到通知中心的传输，每个 CacheManager 使用独立的传输
+// 通知会同步投递给所有已订阅的传输
+func (h *MemoryInvalidationHub) Transport() InvalidationTransport {
+	return &memoryInvalidationTransport{hub: h}
+}
+
+func (m *memoryInvalidationTransport) Publish(payload []byte) error {
+	m.hub.lock.RLock()
+	handlers := make([]func(payload []byte), 0, len(m.hub.handlers))
+	for _, handler := range m.hub.handlers {
+		handlers = append(handlers, handler)
+	}
+	m.hub.lock.RUnlock()
+
+	for _, handler := range handlers {
+		handler(payload)
+	}
+	return nil
+}
+
+func (m *memoryInvalidationTransport) Subscribe(handler func(payload []byte)) error {
+	m.hub.lock.Lock()
+	defer m.hub.lock.Unlock()
+
+	m.hub.handlers[m] = handler
+	return nil
+}
+
+func (m *memoryInvalidationTransport) Close() error {
+	m.hub.lock.Lock()
+	defer m.hub.lock.Unlock()
+
+	delete(m.hub.handlers, m)
+	return nil
+}
@@ -0,0 +1,162 @@
+package caching
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// newTestInvalidationNode 创建一个使用内存缓存桶并开启失效通知的节点
+func newTestInvalidationNode(t *testing.T, transport InvalidationTransport, bucketName BucketName) *CacheManager {
+	t.Helper()
+
+	manager := NewCacheManager().AddBucket(bucketName, NewSimpleMemoryBucket(0, 0))
+	if err := manager.EnableInvalidation(transport); err != nil {
+		t.Fatalf("enable invalidation: %v", err)
+	}
+	t.Cleanup(func() { _ = transport.Close() })
+	return manager
+}
+
+func putOnNodes(t *testing.T, bucketName BucketName, key CacheKey, tags []string, nodes ...*CacheManager) {
+	t.Helper()
+
+	for _, node := range nodes {
+		if err := node.PutWithTags(bucketName, key, "value", tags); err != nil {
+			t.Fatalf("put on node: %v", err)
+		}
+	}
+}
+
+func TestCacheManagerInvalidationEvict(t *testing.T) {
+	hub := NewMemoryInvalidationHub()
+	bucketName := NewBucketName("local")
+	nodeA := newTestInvalidationNode(t, hub.Transport(), bucketName)
+	nodeB := newTestInvalidationNode(t, hub.Transport(), bucketName)
+	key := NewCacheKey("user:1")
+	putOnNodes(t, bucketName, key, nil, nodeA, nodeB)
+
+	if nodeA.NodeID() == "" || nodeA.NodeID() == nodeB.NodeID() {
+		t.Fatalf("expected distinct node ids, got %q and %q", nodeA.NodeID(), nodeB.NodeID())
+	}
+	if err := nodeA.EnableInvalidation(hub.Transport()); !errors.Is(err, toolkitError.ErrInvalidationEnabled) {
+		t.Fatalf("expected ErrInvalidationEnabled, got %v", err)
+	}
+	if err := nodeA.Evict(bucketName, key); err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+	if _, err := nodeB.GetBytes(bucketName, key); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected eviction to reach peer, got %v", err)
+	}
+}
+
+func TestCacheManagerInvalidationIgnoresOwnEvents(t *testing.T) {
+	hub := NewMemoryInvalidationHub()
+	bucketName := NewBucketName("local")
+	nodeA := newTestInvalidationNode(t, hub.Transport(), bucketName)
+	nodeB := newTestInvalidationNode(t, hub.Transport(), bucketName)
+	key := NewCacheKey("user:2")
+	putOnNodes(t, bucketName, key, nil, nodeA, nodeB)
+
+	// 通知中心也会把通知投递回发出的节点
+	if err := nodeA.PublishInvalidation(bucketName, key.RawKeyString()); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, err := nodeA.GetBytes(bucketName, key); err != nil {
+		t.Fatalf("expected own event to be ignored, got %v", err)
+	}
+	if _, err := nodeB.GetBytes(bucketName, key); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected peer to apply event, got %v", err)
+	}
+}
+
+func TestCacheManagerInvalidationByTagAndPrefix(t *testing.T) {
+	hub := NewMemoryInvalidationHub()
+	bucketName := NewBucketName("local")
+	nodeA := newTestInvalidationNode(t, hub.Transport(), bucketName)
+	nodeB := newTestInvalidationNode(t, hub.Transport(), bucketName)
+	tagged := NewCacheKey("profile:3")
+	prefixed := NewCacheKey("orders:3:1")
+	putOnNodes(t, bucketName, tagged, []string{"user:3"}, nodeA, nodeB)
+	putOnNodes(t, bucketName, prefixed, nil, nodeA, nodeB)
+
+	if _, err := nodeA.EvictByTag(bucketName, "user:3"); err != nil {
+		t.Fatalf("evict by tag: %v", err)
+	}
+	if _, err := nodeB.GetBytes(bucketName, tagged); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected tag eviction to reach peer, got %v", err)
+	}
+	if _, err := nodeA.EvictByPrefix(bucketName, "orders:3:"); err != nil {
+		t.Fatalf("evict by prefix: %v", err)
+	}
+	if _, err := nodeB.GetBytes(bucketName, prefixed); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected prefix eviction to reach peer, got %v", err)
+	}
+}
+
+func TestCacheManagerInvalidationEvictsLocalTierOnly(t *testing.T) {
+	hub := NewMemoryInvalidationHub()
+	bucketName := NewBucketName("tiered")
+	remote := newTestMemoryBucket(t, MemoryBucketOption{})
+	local := newTestMemoryBucket(t, MemoryBucketOption{})
+	nodeA := newTestInvalidationNode(t, hub.Transport(), bucketName)
+	nodeB := NewCacheManager().AddBucket(bucketName, newTestTieredBucket(t, local, remote))
+	if err := nodeB.EnableInvalidation(hub.Transport()); err != nil {
+		t.Fatalf("enable invalidation: %v", err)
+	}
+	key := NewCacheKey("shared:1")
+	if err := nodeB.PutBytes(bucketName, key, []byte("shared")); err != nil {
+		t.Fatalf("put tiered: %v", err)
+	}
+
+	if err := nodeA.PublishInvalidation(bucketName, key.RawKeyString()); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, err := local.GetBytes(key); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected local tier to be evicted, got %v", err)
+	}
+	if _, err := remote.GetBytes(key); err != nil {
+		t.Fatalf("expected remote tier to be kept, got %v", err)
+	}
+}
+
+func TestUDPInvalidationTransport(t *testing.T) {
+	transportA, err := NewUDPInvalidationTransport(UDPInvalidationOption{ListenAddr: "127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("new udp transport: %v", err)
+	}
+	transportB, err := NewUDPInvalidationTransport(UDPInvalidationOption{
+		ListenAddr: "127.0.0.1:0",
+		Peers:      []string{transportA.LocalAddr().String()},
+	})
+	if err != nil {
+		t.Fatalf("new udp transport: %v", err)
+	}
+	if err = transportA.AddPeer(transportB.LocalAddr().String()); err != nil {
+		t.Fatalf("add peer: %v", err)
+	}
+	bucketName := NewBucketName("local")
+	nodeA := newTestInvalidationNode(t, transportA, bucketName)
+	nodeB := newTestInvalidationNode(t, transportB, bucketName)
+	key := NewCacheKey("udp:1")
+	putOnNodes(t, bucketName, key, nil, nodeA, nodeB)
+
+	if err = nodeB.Evict(bucketName, key); err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err = nodeA.GetBytes(bucketName, key); errors.Is(err, toolkitError.ErrCacheMiss) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected udp eviction to reach peer, got %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err = transportA.Publish(make([]byte, udpMaxPayload+1)); !errors.Is(err, toolkitError.ErrInvalidationTooLarge) {
+		t.Fatalf("expected ErrInvalidationTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package caching
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+// udpMaxPayload 单个 UDP 报文可携带的最大数据长度
+const udpMaxPayload = 65507
+
+const (
+	// udpMinRetryDelay 接收失败后首次重试前的等待时间
+	udpMinRetryDelay = 10 * time.Millisecond
+	// udpMaxRetryDelay 连续接收失败时重试等待时间的上限
+	udpMaxRetryDelay = time.Second
+)
+
+// UDPInvalidationOption 表示创建 UDP 失效通知传输时使用的配置
+type UDPInvalidationOption struct {
+	// ListenAddr 单播模式下监听的地址，例如 "0.0.0.0:7946"
+	ListenAddr string
+	// Peers 单播模式下其他节点的地址
+	Peers []string
+	// MulticastAddr 组播地址，例如 "239.0.0.1:7946"，设置后使用组播模式并忽略 ListenAddr 和 Peers
+	MulticastAddr string
+	// Interface 组播使用的网卡，为 nil 时由系统选择
+	Interface *net.Interface
+}
+
+// UDPInvalidationTransport 基于 UDP 的失效通知传输，支持单播到固定节点列表或组播
+// UDP 不保证送达，适合配合较短的缓存过期时间使用
+type UDPInvalidationTransport struct {
+	conn    *net.UDPConn
+	sender  *net.UDPConn
+	group   *net.UDPAddr
+	lock    sync.RWMutex
+	peers   []*net.UDPAddr
+	handler atomic.Value
+	once    sync.Once
+	closed  atomic.Bool
+}
+
+// NewUDPInvalidationTransport 创建 UDP 失效通知传输
+func NewUDPInvalidationTransport(option UDPInvalidationOption) (*UDPInvalidationTransport, error) {
+	if option.MulticastAddr != "" {
+		group, err := net.ResolveUDPAddr("udp", option.MulticastAddr)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.ListenMulticastUDP("udp", option.Interface, group)
+		if err != nil {
+			return nil, err
+		}
+		sender, err := net.ListenUDP("udp", nil)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return &UDPInvalidationTransport{conn: conn, sender: sender, group: group}, nil
+	}
+
+	listenAddr, err := net.ResolveUDPAddr("udp", option.ListenAddr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+	transport := &UDPInvalidationTransport{conn: conn}
+	for _, peer := range option.Peers {
+		if err = transport.AddPeer(peer); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return transport, nil
+}
+
+// LocalAddr 获取监听的本地地址
+func (u *UDPInvalidationTransport) LocalAddr() net.Addr {
+	return u.conn.LocalAddr()
+}
+
+// AddPeer 单播模式下添加一个节点地址
+func (u *UDPInvalidationTransport) AddPeer(addr string) error {
+	peer, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return err
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.peers = append(u.peers, peer)
+	return nil
+}
+
+func (u *UDPInvalidationTransport) Publish(payload []byte) error {
+	if len(payload) > udpMaxPayload {
+		return toolkitError.ErrInvalidationTooLarge
+	}
+	if u.group != nil {
+		_, err := u.sender.WriteToUDP(payload, u.group)
+		return err
+	}
+	u.lock.RLock()
+	peers := u.peers
+	u.lock.RUnlock()
+
+	var lastErr error
+	for _, peer := range peers {
+		// 单个节点发送失败时继续通知其他节点
+		if _, err := u.conn.WriteToUDP(payload, peer); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Subscribe 注册接收通知的回调，重复调用时替换之前的回调，回调在接收协程中依次执行
+func (u *UDPInvalidationTransport) Subscribe(handler func(payload []byte)) error {
+	u.handler.Store(handler)
+	u.once.Do(func() {
+		go u.receive()
+	})
+	return nil
+}
+
+func (u *UDPInvalidationTransport) Close() error {
+	u.closed.Store(true)
+	err := u.conn.Close()
+	if u.sender != nil {
+		if senderErr := u.sender.Close(); err == nil {
+			err = senderErr
+		}
+	}
+	return err
+}
+
+func (u *UDPInvalidationTransport) receive() {
+	buf := make([]byte, udpMaxPayload)
+	var delay time.Duration
+	for {
+		n, _, err := u.conn.ReadFromUDP(buf)
+		if err != nil {
+			if u.closed.Load() || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			// 持续失败时逐步延长重试间隔，避免空转占满 CPU 和刷屏日志
+			delay = min(max(delay*2, udpMinRetryDelay), udpMaxRetryDelay)
+			logger.Logrus().Errorln("caching: receive invalidation failed", err)
+			time.Sleep(delay)
+			continue
+		}
+		delay = 0
+		payload := append([]byte(nil), buf[:n]...)
+		u.handler.Load().(func(payload []byte))(payload)
+	}
+}
@@ -0,0 +1,277 @@
+package caching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+const (
+	// SegmentLiteral 固定内容的片段
+	SegmentLiteral KeySegmentType = iota
+	// SegmentString 字符串参数片段
+	SegmentString
+	// SegmentInt 有符号整数参数片段，接受 int、int8、int16、int32、int64
+	SegmentInt
+	// SegmentUint 无符号整数参数片段，接受 uint、uint8、uint16、uint32、uint64
+	SegmentUint
+)
+
+const defaultKeySeparator = ":"
+
+var keySettingMu sync.RWMutex
+var keySettingOnce sync.Once
+var keySettings = KeySettings{Separator: defaultKeySeparator}
+
+// KeySegmentType 表示缓存 key 片段的类型
+type KeySegmentType uint8
+
+// KeySettings 表示通过 KeyBuilder 构建的缓存 key 使用的全局配置
+type KeySettings struct {
+	// Namespace 所有 key 的命名空间前缀，通常为服务名称
+	Namespace string
+	// Version key 的版本号，数据结构不兼容时修改版本号即可使旧缓存全部失效
+	Version string
+	// Separator 片段之间的分隔符，默认为 ":"
+	Separator string
+	// MaxLength key 超过该长度时将参数部分替换为 sha256 摘要，为 0 时不限制
+	MaxLength int
+}
+
+// SetKeyOption 表示缓存 key 全局配置项
+type SetKeyOption func(*KeySettings)
+
+// GlobalKeySetting 设置缓存 key 的全局配置，仅首次调用生效
+func GlobalKeySetting(opt ...SetKeyOption) {
+	initialized := false
+	keySettingOnce.Do(func() {
+		initialized = true
+		keySettingMu.Lock()
+		defer keySettingMu.Unlock()
+		for _, o := range opt {
+			o(&keySettings)
+		}
+		if keySettings.Separator == "" {
+			keySettings.Separator = defaultKeySeparator
+		}
+	})
+	if !initialized {
+		logger.Logrus().Warningln("caching global key setting already initialized")
+	}
+}
+
+// WithKeyNamespace 设置 key 的命名空间
+func WithKeyNamespace(namespace string) SetKeyOption {
+	return func(settings *KeySettings) {
+		settings.Namespace = namespace
+	}
+}
+
+// WithKeyVersion 设置 key 的版本号
+func WithKeyVersion(version string) SetKeyOption {
+	return func(settings *KeySettings) {
+		settings.Version = version
+	}
+}
+
+// WithKeySeparator 设置片段之间的分隔符
+func WithKeySeparator(separator string) SetKeyOption {
+	return func(settings *KeySettings) {
+		settings.Separator = separator
+	}
+}
+
+// WithKeyMaxLength 设置 key 的最大长度，超过后使用摘要
+func WithKeyMaxLength(maxLength int) SetKeyOption {
+	return func(settings *KeySettings) {
+		settings.MaxLength = maxLength
+	}
+}
+
+func currentKeySettings() KeySettings {
+	keySettingMu.RLock()
+	defer keySettingMu.RUnlock()
+	return keySettings
+}
+
+type keySegment struct {
+	segmentType KeySegmentType
+	// value 参数片段为参数名称，固定片段为片段内容
+	value string
+}
+
+// KeyBuilder 用于声明结构化的缓存 key，代替直接使用 fmt.Sprintf 格式
+//
+//	template := NewKeyBuilder("user").Int("id").Literal("profile").Build()
+//	key, err := template.CacheKey(1) // user:1:profile
+type KeyBuilder struct {
+	segments  []keySegment
+	maxLength int
+}
+
+// KeyTemplate 由 KeyBuilder 构建的缓存 key 模板，可并发使用
+type KeyTemplate struct {
+	segments  []keySegment
+	params    int
+	maxLength int
+}
+
+// NewKeyBuilder 创建缓存 key 构建器，name 作为 key 的第一个固定片段
+func NewKeyBuilder(name string) *KeyBuilder {
+	return &KeyBuilder{segments: []keySegment{{segmentType: SegmentLiteral, value: name}}}
+}
+
+// Literal 添加固定内容的片段
+func (b *KeyBuilder) Literal(value string) *KeyBuilder {
+	b.segments = append(b.segments, keySegment{segmentType: SegmentLiteral, value: value})
+	return b
+}
+
+// String 添加字符串参数片段，参数不能包含分隔符
+func (b *KeyBuilder) String(name string) *KeyBuilder {
+	b.segments = append(b.segments, keySegment{segmentType: SegmentString, value: name})
+	return b
+}
+
+// Int 添加有符号整数参数片段
+func (b *KeyBuilder) Int(name string) *KeyBuilder {
+	b.segments = append(b.segments, keySegment{segmentType: SegmentInt, value: name})
+	return b
+}
+
+// Uint 添加无符号整数参数片段
+func (b *KeyBuilder) Uint(name string) *KeyBuilder {
+	b.segments = append(b.segments, keySegment{segmentType: SegmentUint, value: name})
+	return b
+}
+
+// MaxLength 设置当前 key 的最大长度，覆盖全局配置，为 0 时使用全局配置
+func (b *KeyBuilder) MaxLength(maxLength int) *KeyBuilder {
+	b.maxLength = maxLength
+	return b
+}
+
+// Build 构建 key 模板，之后对构建器的修改不会影响已构建的模板
+func (b *KeyBuilder) Build() KeyTemplate {
+	segments := make([]keySegment, len(b.segments))
+	copy(segments, b.segments)
+	params := 0
+	for _, segment := range segments {
+		if segment.segmentType != SegmentLiteral {
+			params++
+		}
+	}
+	return KeyTemplate{segments: segments, params: params, maxLength: b.maxLength}
+}
+
+// ParamNames 获取所有参数片段的名称
+func (k KeyTemplate) ParamNames() []string {
+	names := make([]string, 0, k.params)
+	for _, segment := range k.segments {
+		if segment.segmentType != SegmentLiteral {
+			names = append(names, segment.value)
+		}
+	}
+	return names
+}
+
+// RawKey 校验参数数量和类型后生成原始 key 字符串，并添加全局命名空间和版本号前缀
+func (k KeyTemplate) RawKey(args ...interface{}) (string, error) {
+	if len(args) != k.params {
+		return "", fmt.Errorf("%w: want %d, got %d", toolkitError.ErrKeyArgsMismatch, k.params, len(args))
+	}
+	settings := currentKeySettings()
+	prefix := make([]string, 0, 2)
+	if settings.Namespace != "" {
+		prefix = append(prefix, settings.Namespace)
+	}
+	if settings.Version != "" {
+		prefix = append(prefix, settings.Version)
+	}
+	if len(k.segments) > 0 {
+		prefix = append(prefix, k.segments[0].value)
+	}
+
+	parts := make([]string, 0, len(k.segments)-1)
+	argIndex := 0
+	for _, segment := range k.segments[1:] {
+		if segment.segmentType == SegmentLiteral {
+			parts = append(parts, segment.value)
+			continue
+		}
+		part, err := formatKeyArg(segment, args[argIndex])
+		if err != nil {
+			return "", err
+		}
+		// 参数中的分隔符会使不同参数生成相同的 key，例如 ("a:b", "c") 与 ("a", "b:c")
+		if segment.segmentType == SegmentString && strings.Contains(part, settings.Separator) {
+			return "", fmt.Errorf("%w: segment %s", toolkitError.ErrKeyArgSeparator, segment.value)
+		}
+		parts = append(parts, part)
+		argIndex++
+	}
+
+	rawKey := strings.Join(append(prefix, parts...), settings.Separator)
+	maxLength := settings.MaxLength
+	if k.maxLength > 0 {
+		maxLength = k.maxLength
+	}
+	if maxLength > 0 && len(rawKey) > maxLength {
+		// 保留可读的前缀，参数部分使用摘要以保证唯一
+		sum := sha256.Sum256([]byte(strings.Join(parts, settings.Separator)))
+		rawKey = strings.Join(append(prefix, "#"+hex.EncodeToString(sum[:])), settings.Separator)
+	}
+	return rawKey, nil
+}
+
+// CacheKey 生成可直接用于 CacheManager 的缓存 key
+func (k KeyTemplate) CacheKey(args ...interface{}) (CacheKey, error) {
+	rawKey, err := k.RawKey(args...)
+	if err != nil {
+		return CacheKey{}, err
+	}
+	// 不带 keyAppend 时 RawKeyString 原样返回 KeyFormat，无需转义 %
+	return NewCacheKey(rawKey), nil
+}
+
+func formatKeyArg(segment keySegment, arg interface{}) (string, error) {
+	switch segment.segmentType {
+	case SegmentString:
+		if value, ok := arg.(string); ok {
+			return value, nil
+		}
+	case SegmentInt:
+		switch value := arg.(type) {
+		case int:
+			return strconv.FormatInt(int64(value), 10), nil
+		case int8:
+			return strconv.FormatInt(int64(value), 10), nil
+		case int16:
+			return strconv.FormatInt(int64(value), 10), nil
+		case int32:
+			return strconv.FormatInt(int64(value), 10), nil
+		case int64:
+			return strconv.FormatInt(value, 10), nil
+		}
+	case SegmentUint:
+		switch value := arg.(type) {
+		case uint:
+			return strconv.FormatUint(uint64(value), 10), nil
+		case uint8:
+			return strconv.FormatUint(uint64(value), 10), nil
+		case uint16:
+			return strconv.FormatUint(uint64(value), 10), nil
+		case uint32:
+			return strconv.FormatUint(uint64(value), 10), nil
+		case uint64:
+			return strconv.FormatUint(value, 10), nil
+		}
+	}
+	return "", fmt.Errorf("%w: segment %s got %T", toolkitError.ErrKeyArgType, segment.value, arg)
+}
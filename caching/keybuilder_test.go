@@ -0,0 +1,102 @@
+package caching
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// resetKeySettings 重置全局 key 配置，使每个测试可以重新设置
+func resetKeySettings(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		keySettingMu.Lock()
+		defer keySettingMu.Unlock()
+		keySettingOnce = sync.Once{}
+		keySettings = KeySettings{Separator: defaultKeySeparator}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestKeyTemplateRawKey(t *testing.T) {
+	resetKeySettings(t)
+	template := NewKeyBuilder("user").Int("id").Literal("field").String("name").Uint("shard").Build()
+
+	rawKey, err := template.RawKey(int64(42), "email", uint8(3))
+	if err != nil || rawKey != "user:42:field:email:3" {
+		t.Fatalf("unexpected raw key %q, %v", rawKey, err)
+	}
+	if names := template.ParamNames(); strings.Join(names, ",") != "id,name,shard" {
+		t.Fatalf("unexpected param names %v", names)
+	}
+	if _, err = template.RawKey(42, "email"); !errors.Is(err, toolkitError.ErrKeyArgsMismatch) {
+		t.Fatalf("expected ErrKeyArgsMismatch, got %v", err)
+	}
+	if _, err = template.RawKey("42", "email", uint(3)); !errors.Is(err, toolkitError.ErrKeyArgType) {
+		t.Fatalf("expected ErrKeyArgType, got %v", err)
+	}
+	if _, err = template.RawKey(42, "email", -3); !errors.Is(err, toolkitError.ErrKeyArgType) {
+		t.Fatalf("expected ErrKeyArgType for signed shard, got %v", err)
+	}
+	if _, err = template.RawKey(42, "a:b", uint(3)); !errors.Is(err, toolkitError.ErrKeyArgSeparator) {
+		t.Fatalf("expected ErrKeyArgSeparator, got %v", err)
+	}
+}
+
+func TestKeyTemplateGlobalSetting(t *testing.T) {
+	resetKeySettings(t)
+	GlobalKeySetting(WithKeyNamespace("order-service"), WithKeyVersion("v2"))
+	// 仅首次调用生效
+	GlobalKeySetting(WithKeyNamespace("ignored"))
+
+	template := NewKeyBuilder("order").Int("id").Build()
+	rawKey, err := template.RawKey(7)
+	if err != nil || rawKey != "order-service:v2:order:7" {
+		t.Fatalf("unexpected namespaced key %q, %v", rawKey, err)
+	}
+}
+
+func TestKeyTemplateHashLongKey(t *testing.T) {
+	resetKeySettings(t)
+	GlobalKeySetting(WithKeyMaxLength(32))
+
+	template := NewKeyBuilder("search").String("query").Build()
+	short, err := template.RawKey("go")
+	if err != nil || short != "search:go" {
+		t.Fatalf("unexpected short key %q, %v", short, err)
+	}
+	long, err := template.RawKey(strings.Repeat("x", 64))
+	if err != nil || !strings.HasPrefix(long, "search:#") || len(long) != len("search:#")+64 {
+		t.Fatalf("unexpected hashed key %q, %v", long, err)
+	}
+	other, _ := template.RawKey(strings.Repeat("y", 64))
+	if other == long {
+		t.Fatal("expected different hashes for different arguments")
+	}
+	unlimited, err := NewKeyBuilder("search").String("query").MaxLength(1024).Build().RawKey(strings.Repeat("x", 64))
+	if err != nil || len(unlimited) != len("search:")+64 {
+		t.Fatalf("expected template max length to override global setting, got %q, %v", unlimited, err)
+	}
+}
+
+func TestKeyTemplateWithCacheManager(t *testing.T) {
+	resetKeySettings(t)
+	manager, bucketName := newTestCacheManager(t)
+	template := NewKeyBuilder("percent").String("value").Build()
+
+	key, err := template.CacheKey("100%")
+	if err != nil {
+		t.Fatalf("cache key: %v", err)
+	}
+	if err = manager.Put(bucketName, key, testUser{Name: "P"}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err = manager.GetBytes(bucketName, NewCacheKey("percent:100%")); err != nil {
+		t.Fatalf("expected raw key to be stored verbatim, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package caching
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+// Loader 缓存未命中时用于加载原始数据的函数
+type Loader func() (any, error)
+
+// LoadOption 表示读穿加载时使用的可选配置
+type LoadOption struct {
+	// TTL 加载结果的过期时间，缓存桶需要实现 TTLCacheBucket，为 0 时使用缓存桶默认的过期策略
+	// 设置 SoftTTL 时同时作为硬过期时间，超过后读取将阻塞等待重新加载
+	TTL time.Duration
+	// SoftTTL 加载结果的软过期时间，超过后读取会立即返回旧值并在后台刷新，同一个 key 的刷新只会同时进行一次
+	// 为 0 时不启用，启用后写入的缓存值带有过期时间头，应通过 CacheManager 的解码方法读取
+	SoftTTL time.Duration
+	// NegativeTTL 加载失败后在该时长内直接返回相同的错误而不再调用 Loader，为 0 时不缓存加载错误
+	NegativeTTL time.Duration
+}
+
+type negativeEntry struct {
+	err      error
+	expireAt time.Time
+}
+
+// negativeCache 记录加载失败的 key 及其错误
+type negativeCache struct {
+	entries sync.Map
+}
+
+func (n *negativeCache) load(flightKey string) error {
+	value, ok := n.entries.Load(flightKey)
+	if !ok {
+		return nil
+	}
+	entry := value.(negativeEntry)
+	if time.Now().After(entry.expireAt) {
+		n.entries.CompareAndDelete(flightKey, value)
+		return nil
+	}
+	return entry.err
+}
+
+func (n *negativeCache) store(flightKey string, err error, ttl time.Duration) {
+	n.entries.Store(flightKey, negativeEntry{err: err, expireAt: time.Now().Add(ttl)})
+}
+
+func (n *negativeCache) delete(flightKey string) {
+	n.entries.Delete(flightKey)
+}
+
+// loadFlightKey 生成用于合并并发加载的 key，不同桶中的相同原始 key 互不影响
+func loadFlightKey(bucketName BucketName, rawKey string) string {
+	return string(bucketName) + "\x00" + rawKey
+}
+
+// GetOrLoad 获取缓存，未命中时调用 loader 加载并写入缓存
+// 同一个桶内相同原始 key 的并发未命中只会调用一次 loader，加载错误不会被缓存
+func (c *CacheManager) GetOrLoad(bucketName BucketName, key CacheKey, result any, loader Loader, keyAppend ...interface{}) error {
+	return c.GetOrLoadWithOption(bucketName, key, result, loader, LoadOption{}, keyAppend...)
+}
+
+// GetOrLoadWithOption 按指定配置获取缓存，未命中时调用 loader 加载并写入缓存
+// 设置了 TTL 但缓存桶未实现 TTLCacheBucket 时返回 ErrTTLNotSupported
+func (c *CacheManager) GetOrLoadWithOption(bucketName BucketName, key CacheKey, result any, loader Loader, option LoadOption, keyAppend ...interface{}) error {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return toolkitError.ErrBadBucketName
+	}
+	if option.TTL > 0 {
+		if _, ok := bucket.(TTLCacheBucket); !ok {
+			return toolkitError.ErrTTLNotSupported
+		}
+	}
+	rawKey := key.RawKeyString(keyAppend...)
+	flightKey := loadFlightKey(bucketName, rawKey)
+	bs, err := bucket.GetBytes(key, keyAppend...)
+	if err == nil {
+		switch freshnessOf(bs, time.Now()) {
+		case entryFresh:
+			c.recordGet(bucketName, nil)
+			return c.decode(bucketName, rawKey, bs, result)
+		case entryStale:
+			c.recordGet(bucketName, nil)
+			c.record(bucketName, metricStaleHit, 1, nil)
+			c.refresh(bucketName, bucket, key, loader, option, flightKey, keyAppend...)
+			return c.decode(bucketName, rawKey, bs, result)
+		}
+		// 超过硬过期时间的缓存值视为未命中
+		err = toolkitError.ErrCacheMiss
+	}
+	c.recordGet(bucketName, err)
+	if !errors.Is(err, toolkitError.ErrCacheMiss) {
+		return err
+	}
+
+	if err = c.negatives.load(flightKey); err != nil {
+		return err
+	}
+	value, err, _ := c.loadGroup.Do(flightKey, func() (any, error) {
+		// 等待期间可能已有其他调用完成加载
+		if bs, err := bucket.GetBytes(key, keyAppend...); err == nil && freshnessOf(bs, time.Now()) != entryExpired {
+			return bs, nil
+		}
+		return c.load(bucketName, bucket, key, loader, option, flightKey, keyAppend...)
+	})
+	if err != nil {
+		return err
+	}
+	return c.decode(bucketName, rawKey, value.([]byte), result)
+}
+
+// refresh 在后台重新加载软过期的缓存值，与同一个 key 的其他加载合并
+func (c *CacheManager) refresh(bucketName BucketName, bucket CacheBucket, key CacheKey, loader Loader, option LoadOption, flightKey string, keyAppend ...interface{}) {
+	// 最近一次加载失败时不再反复刷新，继续返回旧值
+	if c.negatives.load(flightKey) != nil {
+		return
+	}
+	c.loadGroup.DoChan(flightKey, func() (any, error) {
+		if bs, err := bucket.GetBytes(key, keyAppend...); err == nil && freshnessOf(bs, time.Now()) == entryFresh {
+			return bs, nil
+		}
+		bs, err := c.load(bucketName, bucket, key, loader, option, flightKey, keyAppend...)
+		if err != nil {
+			logger.Logrus().Errorln("caching: refresh stale value failed", bucketName, err)
+		}
+		return bs, err
+	})
+}
+
+// load 调用 loader 并将编码后的结果写入缓存
+func (c *CacheManager) load(bucketName BucketName, bucket CacheBucket, key CacheKey, loader Loader, option LoadOption, flightKey string, keyAppend ...interface{}) ([]byte, error) {
+	start := time.Now()
+	data, err := loader()
+	c.recordLoad(bucketName, time.Since(start), err)
+	if err != nil {
+		if option.NegativeTTL > 0 {
+			c.negatives.store(flightKey, err, option.NegativeTTL)
+		}
+		return nil, err
+	}
+	bs, err := c.encode(key.RawKeyString(keyAppend...), data)
+	if err != nil {
+		return nil, err
+	}
+	if option.SoftTTL > 0 {
+		bs = withFreshness(bs, time.Now(), option.SoftTTL, option.TTL)
+	}
+	if err = putLoadedBytes(bucket, key, bs, option.TTL, keyAppend...); err != nil {
+		logger.Logrus().Errorln("caching: put loaded value failed", bucketName, err)
+	} else {
+		c.record(bucketName, metricPut, 1, nil)
+	}
+	return bs, nil
+}
+
+func putLoadedBytes(bucket CacheBucket, key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error {
+	if ttl <= 0 {
+		return bucket.PutBytes(key, data, keyAppend...)
+	}
+	ttlBucket, ok := bucket.(TTLCacheBucket)
+	if !ok {
+		return toolkitError.ErrTTLNotSupported
+	}
+	return ttlBucket.PutBytesWithTTL(key, data, ttl, keyAppend...)
+}
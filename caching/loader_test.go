@@ -0,0 +1,283 @@
+package caching
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestCacheManagerGetOrLoad(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("load:%d")
+	want := testUser{Name: "Loaded", Sex: 1}
+	var calls int32
+
+	loader := func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		return want, nil
+	}
+	for i := 0; i < 2; i++ {
+		var got testUser
+		if err := manager.GetOrLoad(bucketName, key, &got, loader, 1); err != nil {
+			t.Fatalf("get or load: %v", err)
+		}
+		if got != want {
+			t.Fatalf("unexpected loaded value: got %+v, want %+v", got, want)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected loader to run once, got %d", calls)
+	}
+}
+
+func TestCacheManagerGetOrLoadMergesConcurrentMisses(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("load:concurrent")
+	var calls int32
+	release := make(chan struct{})
+
+	loader := func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var got string
+			if err := manager.GetOrLoad(bucketName, key, &got, loader); err != nil {
+				errs <- err
+				return
+			}
+			if got != "value" {
+				errs <- errors.New("unexpected value " + got)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent get or load: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected loader to run once, got %d", calls)
+	}
+}
+
+func TestCacheManagerGetOrLoadDoesNotCacheErrors(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("load:error")
+	loadErr := errors.New("db down")
+	var calls int32
+
+	loader := func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, loadErr
+	}
+	for i := 0; i < 2; i++ {
+		var got string
+		if err := manager.GetOrLoad(bucketName, key, &got, loader); !errors.Is(err, loadErr) {
+			t.Fatalf("expected loader error, got %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected loader to run twice, got %d", calls)
+	}
+}
+
+func TestCacheManagerGetOrLoadNegativeTTL(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("load:negative")
+	loadErr := errors.New("not found")
+	var calls int32
+	option := LoadOption{NegativeTTL: 50 * time.Millisecond}
+
+	loader := func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, loadErr
+	}
+	for i := 0; i < 3; i++ {
+		var got string
+		if err := manager.GetOrLoadWithOption(bucketName, key, &got, loader, option); !errors.Is(err, loadErr) {
+			t.Fatalf("expected loader error, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected negative cache to suppress loader, got %d calls", calls)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	var got string
+	_ = manager.GetOrLoadWithOption(bucketName, key, &got, loader, option)
+	if calls != 2 {
+		t.Fatalf("expected loader to run after negative ttl, got %d calls", calls)
+	}
+
+	if err := manager.Put(bucketName, key, "fresh"); err != nil {
+		t.Fatalf("put cache: %v", err)
+	}
+	if err := manager.GetOrLoadWithOption(bucketName, key, &got, loader, option); err != nil || got != "fresh" {
+		t.Fatalf("expected put to clear negative entry, got %q, %v", got, err)
+	}
+}
+
+func TestTypedCacheGetOrLoad(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	users := NewTypedCache[int, testUser](manager, bucketName, NewCacheKey("typed:load:%d"))
+	want := testUser{Name: "TypedLoad", Sex: 2}
+
+	got, err := users.GetOrLoad(3, func() (testUser, error) {
+		return want, nil
+	})
+	if err != nil {
+		t.Fatalf("typed get or load: %v", err)
+	}
+	if got != want {
+		t.Fatalf("unexpected typed loaded value: got %+v, want %+v", got, want)
+	}
+	if cached, err := users.Get(3); err != nil || cached != want {
+		t.Fatalf("expected loaded value to be cached, got %+v, %v", cached, err)
+	}
+}
+
+func TestCacheManagerGetOrLoadSoftTTL(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	observer := &countingObserver{}
+	manager.SetMetricsObserver(observer)
+	key := NewCacheKey("swr:%d")
+	option := LoadOption{SoftTTL: 20 * time.Millisecond, TTL: time.Minute}
+	var calls int32
+	release := make(chan struct{})
+
+	loader := func() (any, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n > 1 {
+			<-release
+		}
+		return testUser{Name: "v", Sex: uint8(n)}, nil
+	}
+	var got testUser
+	if err := manager.GetOrLoadWithOption(bucketName, key, &got, loader, option, 1); err != nil || got.Sex != 1 {
+		t.Fatalf("unexpected first load %+v, %v", got, err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	// 软过期后的并发读取立即返回旧值，且只触发一次后台刷新
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var stale testUser
+			if err := manager.GetOrLoadWithOption(bucketName, key, &stale, loader, option, 1); err != nil || stale.Sex != 1 {
+				t.Errorf("expected stale value, got %+v, %v", stale, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if err := manager.Get(bucketName, key, &got, 1); err == nil && got.Sex == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected refreshed value, got %+v", got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected one background refresh, got %d loads", n)
+	}
+	stats, err := manager.Stats(bucketName)
+	if err != nil || stats.StaleHits != 10 {
+		t.Fatalf("unexpected stale hits %+v, %v", stats, err)
+	}
+	if observer.staleHits != 10 {
+		t.Fatalf("expected observer stale hits 10, got %d", observer.staleHits)
+	}
+}
+
+func TestCacheManagerGetOrLoadHardTTL(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("swr:hard")
+	option := LoadOption{SoftTTL: 10 * time.Millisecond, TTL: 30 * time.Millisecond}
+	var calls int32
+
+	loader := func() (any, error) {
+		return testUser{Sex: uint8(atomic.AddInt32(&calls, 1))}, nil
+	}
+	var got testUser
+	if err := manager.GetOrLoadWithOption(bucketName, key, &got, loader, option); err != nil || got.Sex != 1 {
+		t.Fatalf("unexpected first load %+v, %v", got, err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	// 直接读取超过硬过期时间的缓存值视为未命中
+	if err := manager.Get(bucketName, key, &got); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after hard ttl, got %v", err)
+	}
+	batch, err := manager.GetMany(bucketName, key, [][]interface{}{{}})
+	if err != nil {
+		t.Fatalf("get many: %v", err)
+	}
+	if err = batch.Decode(0, &got); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected batch ErrCacheMiss after hard ttl, got %v", err)
+	}
+	// 超过硬过期时间后阻塞等待重新加载
+	if err := manager.GetOrLoadWithOption(bucketName, key, &got, loader, option); err != nil || got.Sex != 2 {
+		t.Fatalf("expected blocking reload after hard ttl, got %+v, %v", got, err)
+	}
+}
+
+func TestCacheManagerGetOrLoadTTLNotSupported(t *testing.T) {
+	bucketName := NewBucketName("plain")
+	manager := NewCacheManager().AddBucket(bucketName, plainBucket{CacheBucket: newTestMemoryBucket(t, MemoryBucketOption{})})
+	var calls int
+	loader := func() (any, error) {
+		calls++
+		return testUser{Name: "T"}, nil
+	}
+
+	var got testUser
+	err := manager.GetOrLoadWithOption(bucketName, NewCacheKey("plain:ttl"), &got, loader, LoadOption{TTL: time.Minute})
+	if !errors.Is(err, toolkitError.ErrTTLNotSupported) {
+		t.Fatalf("expected ErrTTLNotSupported, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected loader not called, got %d", calls)
+	}
+}
+
+func TestFreshnessOf(t *testing.T) {
+	now := time.Now()
+	bs := withFreshness([]byte("body"), now, time.Second, time.Minute)
+	if body, _, _, ok := parseFreshness(bs); !ok || string(body) != "body" {
+		t.Fatalf("unexpected freshness body %q, %v", body, ok)
+	}
+	if got := freshnessOf(bs, now); got != entryFresh {
+		t.Fatalf("expected fresh, got %v", got)
+	}
+	if got := freshnessOf(bs, now.Add(2*time.Second)); got != entryStale {
+		t.Fatalf("expected stale, got %v", got)
+	}
+	if got := freshnessOf(bs, now.Add(2*time.Minute)); got != entryExpired {
+		t.Fatalf("expected expired, got %v", got)
+	}
+	if got := freshnessOf(withFreshness(nil, now, time.Second, 0), now.Add(time.Hour)); got != entryStale {
+		t.Fatalf("expected stale without hard ttl, got %v", got)
+	}
+	if got := freshnessOf([]byte("plain"), now); got != entryFresh {
+		t.Fatalf("expected plain value to be fresh, got %v", got)
+	}
+}
@@ -0,0 +1,282 @@
+package caching
+
+import (
+	"sync"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+const (
+	// EvictReasonCapacity 表示缓存项因超过容量上限被淘汰
+	EvictReasonCapacity EvictReason = iota
+	// EvictReasonExpired 表示缓存项因过期被清除
+	EvictReasonExpired
+)
+
+// EvictReason 表示缓存项被内存缓存桶自动清除的原因
+type EvictReason uint8
+
+// MemoryBucketOption 表示创建内存缓存桶时使用的配置
+type MemoryBucketOption struct {
+	// Policy 淘汰策略，默认 LRU
+	Policy EvictionPolicy
+	// MaxEntries 最大缓存项数量，为 0 时不限制
+	MaxEntries int
+	// MaxBytes 最大占用字节数（key 与值长度之和），为 0 时不限制
+	MaxBytes int64
+	// DefaultTTL 未单独指定过期时间时使用的过期时间，为 0 时永不过期
+	DefaultTTL time.Duration
+	// OnEvict 缓存项因容量或过期被自动清除时的回调，主动 Evict 不会触发，回调在锁外执行
+	OnEvict func(rawKey string, data []byte, reason EvictReason)
+}
+
+type memoryEntry struct {
+	data     []byte
+	size     int64
+	expireAt time.Time
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+type memoryEvicted struct {
+	rawKey string
+	data   []byte
+	reason EvictReason
+}
+
+// MemoryBucket 进程内缓存桶，支持按数量和字节数限制容量，并按指定策略淘汰
+// 过期的缓存项在读取或因容量被淘汰时清除，没有后台清理协程，需要及时释放内存时可定期调用 PurgeExpired
+type MemoryBucket struct {
+	lock      sync.Mutex
+	option    MemoryBucketOption
+	entries   map[string]*memoryEntry
+	usedBytes int64
+	policy    evictionPolicy
+}
+
+// NewMemoryBucket 使用指定配置创建内存缓存桶
+func NewMemoryBucket(option MemoryBucketOption) (*MemoryBucket, error) {
+	policy, ok := newEvictionPolicy(option.Policy, option.MaxEntries)
+	if !ok {
+		return nil, toolkitError.ErrUnsupportedEvictionPolicy
+	}
+	return &MemoryBucket{
+		option:  option,
+		entries: make(map[string]*memoryEntry),
+		policy:  policy,
+	}, nil
+}
+
+// NewSimpleMemoryBucket 创建按 LRU 淘汰的内存缓存桶
+func NewSimpleMemoryBucket(maxEntries int, defaultTTL time.Duration) *MemoryBucket {
+	bucket, _ := NewMemoryBucket(MemoryBucketOption{
+		Policy:     EvictionPolicyLRU,
+		MaxEntries: maxEntries,
+		DefaultTTL: defaultTTL,
+	})
+	return bucket
+}
+
+// Len 获取当前缓存项数量，包含已过期但尚未清除的缓存项
+func (m *MemoryBucket) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return len(m.entries)
+}
+
+// UsedBytes 获取当前占用的字节数
+func (m *MemoryBucket) UsedBytes() int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.usedBytes
+}
+
+func (m *MemoryBucket) GetBytes(key CacheKey, keyAppend ...interface{}) ([]byte, error) {
+	data, _, err := m.getEntry(key.RawKeyString(keyAppend...))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *MemoryBucket) PutBytes(key CacheKey, data []byte, keyAppend ...interface{}) error {
+	return m.PutBytesWithTTL(key, data, m.option.DefaultTTL, keyAppend...)
+}
+
+// PutBytesWithTTL 设置key对应的字节数组，并指定过期时间
+func (m *MemoryBucket) PutBytesWithTTL(key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error {
+	rawKey := key.RawKeyString(keyAppend...)
+	size := int64(len(rawKey) + len(data))
+	if m.option.MaxBytes > 0 && size > m.option.MaxBytes {
+		return toolkitError.ErrCacheEntryTooLarge
+	}
+	entry := &memoryEntry{
+		data: append([]byte(nil), data...),
+		size: size,
+	}
+
+	now := time.Now()
+	m.lock.Lock()
+	old, exists := m.entries[rawKey]
+	var evicted []memoryEvicted
+	for m.overflow(entry.size, old, exists) {
+		victim, ok := m.policy.evict()
+		if !ok {
+			break
+		}
+		if victim == rawKey {
+			// 被覆盖的旧值无需回调
+			m.deleteEntry(rawKey)
+			exists = false
+			continue
+		}
+		victimEntry := m.entries[victim]
+		m.deleteEntry(victim)
+		reason := EvictReasonCapacity
+		if victimEntry.expired(now) {
+			reason = EvictReasonExpired
+		}
+		evicted = append(evicted, memoryEvicted{rawKey: victim, data: victimEntry.data, reason: reason})
+	}
+	if exists {
+		m.usedBytes -= old.size
+		m.policy.touch(rawKey)
+	} else {
+		m.policy.add(rawKey)
+	}
+	if ttl > 0 {
+		entry.expireAt = now.Add(ttl)
+	}
+	m.entries[rawKey] = entry
+	m.usedBytes += entry.size
+	m.lock.Unlock()
+
+	m.notifyEvicted(evicted...)
+	return nil
+}
+
+// TTL 获取key剩余的过期时间，返回 0 表示未设置过期时间
+func (m *MemoryBucket) TTL(key CacheKey, keyAppend ...interface{}) (time.Duration, error) {
+	_, expireAt, err := m.getEntry(key.RawKeyString(keyAppend...))
+	if err != nil {
+		return 0, err
+	}
+	if expireAt.IsZero() {
+		return 0, nil
+	}
+	return time.Until(expireAt), nil
+}
+
+func (m *MemoryBucket) Evict(key CacheKey, keyAppend ...interface{}) error {
+	rawKey := key.RawKeyString(keyAppend...)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.entries[rawKey]; ok {
+		m.policy.remove(rawKey)
+		m.deleteEntry(rawKey)
+	}
+	return nil
+}
+
+// PurgeExpired 清除所有已过期的缓存项并返回清除的数量，清除的缓存项以 EvictReasonExpired 触发 OnEvict
+func (m *MemoryBucket) PurgeExpired() int {
+	now := time.Now()
+	m.lock.Lock()
+	var evicted []memoryEvicted
+	for rawKey, entry := range m.entries {
+		if entry.expired(now) {
+			m.policy.remove(rawKey)
+			m.deleteEntry(rawKey)
+			evicted = append(evicted, memoryEvicted{rawKey: rawKey, data: entry.data, reason: EvictReasonExpired})
+		}
+	}
+	m.lock.Unlock()
+
+	m.notifyEvicted(evicted...)
+	return len(evicted)
+}
+
+// Range 遍历所有未过期的缓存项，遍历不会影响淘汰顺序，fn 在锁外执行
+func (m *MemoryBucket) Range(fn func(rawKey string, data []byte, ttl time.Duration) bool) error {
+	type rangeEntry struct {
+		rawKey   string
+		data     []byte
+		expireAt time.Time
+	}
+	now := time.Now()
+	m.lock.Lock()
+	entries := make([]rangeEntry, 0, len(m.entries))
+	for rawKey, entry := range m.entries {
+		if !entry.expired(now) {
+			entries = append(entries, rangeEntry{rawKey: rawKey, data: entry.data, expireAt: entry.expireAt})
+		}
+	}
+	m.lock.Unlock()
+
+	for _, entry := range entries {
+		var ttl time.Duration
+		if !entry.expireAt.IsZero() {
+			ttl = entry.expireAt.Sub(now)
+		}
+		if !fn(entry.rawKey, append([]byte(nil), entry.data...), ttl) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// getEntry 获取未过期的缓存项并记录访问，已过期的缓存项将被清除并视为未命中
+func (m *MemoryBucket) getEntry(rawKey string) ([]byte, time.Time, error) {
+	m.lock.Lock()
+	entry, ok := m.entries[rawKey]
+	if !ok {
+		m.lock.Unlock()
+		return nil, time.Time{}, toolkitError.ErrCacheMiss
+	}
+	if entry.expired(time.Now()) {
+		m.policy.remove(rawKey)
+		m.deleteEntry(rawKey)
+		m.lock.Unlock()
+		m.notifyEvicted(memoryEvicted{rawKey: rawKey, data: entry.data, reason: EvictReasonExpired})
+		return nil, time.Time{}, toolkitError.ErrCacheMiss
+	}
+	m.policy.touch(rawKey)
+	m.lock.Unlock()
+	// 返回副本避免调用方修改缓存内容
+	return append([]byte(nil), entry.data...), entry.expireAt, nil
+}
+
+// overflow 判断写入 size 大小的缓存项后是否超过容量上限
+func (m *MemoryBucket) overflow(size int64, old *memoryEntry, exists bool) bool {
+	count := len(m.entries)
+	usedBytes := m.usedBytes + size
+	if exists {
+		usedBytes -= old.size
+	} else {
+		count++
+	}
+	return (m.option.MaxEntries > 0 && count > m.option.MaxEntries) ||
+		(m.option.MaxBytes > 0 && usedBytes > m.option.MaxBytes)
+}
+
+func (m *MemoryBucket) deleteEntry(rawKey string) {
+	if entry, ok := m.entries[rawKey]; ok {
+		m.usedBytes -= entry.size
+		delete(m.entries, rawKey)
+	}
+}
+
+func (m *MemoryBucket) notifyEvicted(evicted ...memoryEvicted) {
+	if m.option.OnEvict == nil {
+		return
+	}
+	for _, e := range evicted {
+		m.option.OnEvict(e.rawKey, e.data, e.reason)
+	}
+}
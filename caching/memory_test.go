@@ -0,0 +1,202 @@
+package caching
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestMemoryBucket(t *testing.T, option MemoryBucketOption) *MemoryBucket {
+	t.Helper()
+
+	bucket, err := NewMemoryBucket(option)
+	if err != nil {
+		t.Fatalf("new memory bucket: %v", err)
+	}
+	return bucket
+}
+
+func assertMemoryKeys(t *testing.T, bucket *MemoryBucket, present, absent []string) {
+	t.Helper()
+
+	for _, key := range present {
+		if _, err := bucket.GetBytes(NewCacheKey(key)); err != nil {
+			t.Fatalf("expected key %q to be present: %v", key, err)
+		}
+	}
+	for _, key := range absent {
+		if _, err := bucket.GetBytes(NewCacheKey(key)); !errors.Is(err, toolkitError.ErrCacheMiss) {
+			t.Fatalf("expected key %q to be evicted, got %v", key, err)
+		}
+	}
+}
+
+func TestMemoryBucketLRU(t *testing.T) {
+	var evicted []string
+	bucket := newTestMemoryBucket(t, MemoryBucketOption{
+		Policy:     EvictionPolicyLRU,
+		MaxEntries: 2,
+		OnEvict: func(rawKey string, data []byte, reason EvictReason) {
+			if reason != EvictReasonCapacity {
+				t.Errorf("unexpected evict reason %d", reason)
+			}
+			evicted = append(evicted, rawKey)
+		},
+	})
+
+	_ = bucket.PutBytes(NewCacheKey("a"), []byte("1"))
+	_ = bucket.PutBytes(NewCacheKey("b"), []byte("2"))
+	if _, err := bucket.GetBytes(NewCacheKey("a")); err != nil {
+		t.Fatalf("get a: %v", err)
+	}
+	_ = bucket.PutBytes(NewCacheKey("c"), []byte("3"))
+
+	assertMemoryKeys(t, bucket, []string{"a", "c"}, []string{"b"})
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Fatalf("unexpected evicted keys: %v", evicted)
+	}
+}
+
+func TestMemoryBucketLFU(t *testing.T) {
+	bucket := newTestMemoryBucket(t, MemoryBucketOption{Policy: EvictionPolicyLFU, MaxEntries: 2})
+
+	_ = bucket.PutBytes(NewCacheKey("a"), []byte("1"))
+	_ = bucket.PutBytes(NewCacheKey("b"), []byte("2"))
+	for i := 0; i < 3; i++ {
+		_, _ = bucket.GetBytes(NewCacheKey("a"))
+	}
+	_, _ = bucket.GetBytes(NewCacheKey("b"))
+	_ = bucket.PutBytes(NewCacheKey("c"), []byte("3"))
+	_ = bucket.PutBytes(NewCacheKey("d"), []byte("4"))
+
+	assertMemoryKeys(t, bucket, []string{"a", "d"}, []string{"b", "c"})
+}
+
+func TestMemoryBucketARC(t *testing.T) {
+	bucket := newTestMemoryBucket(t, MemoryBucketOption{Policy: EvictionPolicyARC, MaxEntries: 2})
+
+	_ = bucket.PutBytes(NewCacheKey("hot"), []byte("1"))
+	_, _ = bucket.GetBytes(NewCacheKey("hot"))
+	// 一次性扫描的 key 不应挤掉被重复访问的 key
+	for _, key := range []string{"s1", "s2", "s3", "s4"} {
+		_ = bucket.PutBytes(NewCacheKey(key), []byte(key))
+	}
+
+	assertMemoryKeys(t, bucket, []string{"hot", "s4"}, []string{"s1", "s2", "s3"})
+	if bucket.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", bucket.Len())
+	}
+}
+
+func TestMemoryBucketMaxBytes(t *testing.T) {
+	bucket := newTestMemoryBucket(t, MemoryBucketOption{MaxBytes: 10})
+
+	_ = bucket.PutBytes(NewCacheKey("a"), []byte("1234"))
+	_ = bucket.PutBytes(NewCacheKey("b"), []byte("1234"))
+	if got := bucket.UsedBytes(); got != 10 {
+		t.Fatalf("unexpected used bytes: %d", got)
+	}
+	_ = bucket.PutBytes(NewCacheKey("c"), []byte("12"))
+
+	assertMemoryKeys(t, bucket, []string{"b", "c"}, []string{"a"})
+	if err := bucket.PutBytes(NewCacheKey("large"), make([]byte, 11)); !errors.Is(err, toolkitError.ErrCacheEntryTooLarge) {
+		t.Fatalf("expected ErrCacheEntryTooLarge, got %v", err)
+	}
+}
+
+func TestMemoryBucketOverwrite(t *testing.T) {
+	bucket := newTestMemoryBucket(t, MemoryBucketOption{MaxEntries: 1, MaxBytes: 8})
+
+	_ = bucket.PutBytes(NewCacheKey("a"), []byte("1"))
+	if err := bucket.PutBytes(NewCacheKey("a"), []byte("1234567")); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	got, err := bucket.GetBytes(NewCacheKey("a"))
+	if err != nil || string(got) != "1234567" {
+		t.Fatalf("unexpected overwritten value %q, %v", got, err)
+	}
+	if bucket.Len() != 1 || bucket.UsedBytes() != 8 {
+		t.Fatalf("unexpected size after overwrite: %d entries, %d bytes", bucket.Len(), bucket.UsedBytes())
+	}
+}
+
+func TestMemoryBucketTTL(t *testing.T) {
+	var reasons []EvictReason
+	bucket := newTestMemoryBucket(t, MemoryBucketOption{
+		OnEvict: func(rawKey string, data []byte, reason EvictReason) {
+			reasons = append(reasons, reason)
+		},
+	})
+	key := NewCacheKey("ttl")
+
+	if err := bucket.PutBytesWithTTL(key, []byte("v"), 30*time.Millisecond); err != nil {
+		t.Fatalf("put with ttl: %v", err)
+	}
+	if ttl, err := bucket.TTL(key); err != nil || ttl <= 0 {
+		t.Fatalf("expected positive ttl, got %v, %v", ttl, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, err := bucket.GetBytes(key); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after ttl, got %v", err)
+	}
+	if len(reasons) != 1 || reasons[0] != EvictReasonExpired {
+		t.Fatalf("unexpected evict reasons: %v", reasons)
+	}
+}
+
+func TestMemoryBucketExpiredEvictReason(t *testing.T) {
+	reasons := make(map[string]EvictReason)
+	bucket := newTestMemoryBucket(t, MemoryBucketOption{
+		MaxEntries: 2,
+		OnEvict: func(rawKey string, data []byte, reason EvictReason) {
+			reasons[rawKey] = reason
+		},
+	})
+
+	_ = bucket.PutBytesWithTTL(NewCacheKey("a"), []byte("a"), 10*time.Millisecond)
+	_ = bucket.PutBytes(NewCacheKey("b"), []byte("b"))
+	time.Sleep(20 * time.Millisecond)
+	// 因容量被淘汰的缓存项已过期时报告为过期
+	_ = bucket.PutBytes(NewCacheKey("c"), []byte("c"))
+	if reason, ok := reasons["a"]; !ok || reason != EvictReasonExpired {
+		t.Fatalf("expected a evicted as expired, got %v, %v", reason, ok)
+	}
+
+	_ = bucket.PutBytesWithTTL(NewCacheKey("d"), []byte("d"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if n := bucket.PurgeExpired(); n != 1 || bucket.Len() != 1 {
+		t.Fatalf("expected one purged entry, got %d, len %d", n, bucket.Len())
+	}
+	if reasons["b"] != EvictReasonCapacity || reasons["d"] != EvictReasonExpired {
+		t.Fatalf("unexpected evict reasons %v", reasons)
+	}
+}
+
+func TestMemoryBucketWithCacheManager(t *testing.T) {
+	bucketName := NewBucketName("memory")
+	manager := NewCacheManager().AddBucket(bucketName, NewSimpleMemoryBucket(10, time.Minute))
+	key := NewCacheKey("user:%d")
+	want := testUser{Name: "Memory", Sex: 1}
+
+	if err := manager.Put(bucketName, key, want, 1); err != nil {
+		t.Fatalf("put cache: %v", err)
+	}
+	var got testUser
+	if err := manager.Get(bucketName, key, &got, 1); err != nil || got != want {
+		t.Fatalf("unexpected cache value %+v, %v", got, err)
+	}
+	if err := manager.Evict(bucketName, key, 1); err != nil {
+		t.Fatalf("evict cache: %v", err)
+	}
+	if err := manager.Get(bucketName, key, &got, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after evict, got %v", err)
+	}
+}
+
+func TestMemoryBucketUnsupportedPolicy(t *testing.T) {
+	if _, err := NewMemoryBucket(MemoryBucketOption{Policy: EvictionPolicy(99)}); !errors.Is(err, toolkitError.ErrUnsupportedEvictionPolicy) {
+		t.Fatalf("expected ErrUnsupportedEvictionPolicy, got %v", err)
+	}
+}
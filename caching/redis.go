@@ -0,0 +1,163 @@
+package caching
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBucketOption 表示创建 Redis 缓存桶时使用的配置
+type RedisBucketOption struct {
+	// KeyPrefix 所有 key 的前缀，用于在同一个 Redis 中区分不同的缓存桶
+	KeyPrefix string
+	// DefaultTTL 未单独指定过期时间时使用的过期时间，为 0 时永不过期
+	DefaultTTL time.Duration
+}
+
+// RedisBucket 基于 Redis 的缓存桶，适合作为多个节点共享的缓存
+type RedisBucket struct {
+	client redis.UniversalClient
+	option RedisBucketOption
+}
+
+// NewRedisBucketByClient 使用已有的 Redis 客户端创建缓存桶，支持单机、哨兵和集群客户端
+func NewRedisBucketByClient(client redis.UniversalClient, option RedisBucketOption) *RedisBucket {
+	return &RedisBucket{
+		client: client,
+		option: option,
+	}
+}
+
+// NewSimpleRedisBucket 连接指定地址的 Redis 并创建缓存桶
+func NewSimpleRedisBucket(addr string, defaultTTL time.Duration) *RedisBucket {
+	return NewRedisBucketByClient(redis.NewClient(&redis.Options{Addr: addr}), RedisBucketOption{
+		DefaultTTL: defaultTTL,
+	})
+}
+
+// Client 获取底层 Redis 客户端
+func (r *RedisBucket) Client() redis.UniversalClient {
+	return r.client
+}
+
+func (r *RedisBucket) GetBytes(key CacheKey, keyAppend ...interface{}) ([]byte, error) {
+	bs, err := r.client.Get(context.Background(), r.redisKey(key.RawKeyString(keyAppend...))).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, toolkitError.ErrCacheMiss
+		}
+		return nil, err
+	}
+	return bs, nil
+}
+
+func (r *RedisBucket) PutBytes(key CacheKey, data []byte, keyAppend ...interface{}) error {
+	return r.PutBytesWithTTL(key, data, r.option.DefaultTTL, keyAppend...)
+}
+
+// PutBytesWithTTL 设置key对应的字节数组，并指定过期时间
+func (r *RedisBucket) PutBytesWithTTL(key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error {
+	return r.client.Set(context.Background(), r.redisKey(key.RawKeyString(keyAppend...)), data, redisExpiration(ttl)).Err()
+}
+
+// TTL 获取key剩余的过期时间，返回 0 表示未设置过期时间
+func (r *RedisBucket) TTL(key CacheKey, keyAppend ...interface{}) (time.Duration, error) {
+	ttl, err := r.client.PTTL(context.Background(), r.redisKey(key.RawKeyString(keyAppend...))).Result()
+	if err != nil {
+		return 0, err
+	}
+	switch ttl {
+	case -2:
+		// key 不存在
+		return 0, toolkitError.ErrCacheMiss
+	case -1:
+		// key 未设置过期时间
+		return 0, nil
+	default:
+		return ttl, nil
+	}
+}
+
+func (r *RedisBucket) Evict(key CacheKey, keyAppend ...interface{}) error {
+	return r.client.Del(context.Background(), r.redisKey(key.RawKeyString(keyAppend...))).Err()
+}
+
+// GetBytesBatch 通过 pipeline 批量获取原始 key 对应的值，返回结果与 rawKeys 顺序一致，未命中的位置为 nil
+func (r *RedisBucket) GetBytesBatch(rawKeys []string) ([][]byte, error) {
+	if len(rawKeys) == 0 {
+		return nil, nil
+	}
+	// 集群模式下多个 key 可能位于不同的 slot，不使用 MGET
+	commands := make([]*redis.StringCmd, len(rawKeys))
+	_, err := r.client.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for i, rawKey := range rawKeys {
+			commands[i] = pipe.Get(context.Background(), r.redisKey(rawKey))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	result := make([][]byte, len(rawKeys))
+	for i, command := range commands {
+		bs, err := command.Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, err
+		}
+		if bs == nil {
+			bs = []byte{}
+		}
+		result[i] = bs
+	}
+	return result, nil
+}
+
+// PutBytesBatch 通过 pipeline 批量设置原始 key 对应的字节数组，使用默认过期时间
+func (r *RedisBucket) PutBytesBatch(rawKeys []string, data [][]byte) error {
+	if len(rawKeys) != len(data) {
+		return toolkitError.ErrBatchSizeMismatch
+	}
+	if len(rawKeys) == 0 {
+		return nil
+	}
+	expiration := redisExpiration(r.option.DefaultTTL)
+	_, err := r.client.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for i, rawKey := range rawKeys {
+			pipe.Set(context.Background(), r.redisKey(rawKey), data[i], expiration)
+		}
+		return nil
+	})
+	return err
+}
+
+// EvictBatch 批量清除原始 key
+func (r *RedisBucket) EvictBatch(rawKeys []string) error {
+	if len(rawKeys) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for _, rawKey := range rawKeys {
+			pipe.Del(context.Background(), r.redisKey(rawKey))
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *RedisBucket) redisKey(rawKey string) string {
+	return r.option.KeyPrefix + rawKey
+}
+
+// redisExpiration 将过期时间转换为 Redis 的过期参数，0 表示永不过期
+func redisExpiration(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return 0
+	}
+	return ttl
+}
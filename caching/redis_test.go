@@ -0,0 +1,273 @@
+package caching
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/redis/go-redis/v9"
+)
+
+type respEntry struct {
+	value    string
+	expireAt time.Time
+}
+
+// respServer 进程内的 RESP 协议服务端，仅实现缓存桶用到的少量命令
+type respServer struct {
+	listener net.Listener
+	lock     sync.Mutex
+	data     map[string]respEntry
+}
+
+func newRespServer(t *testing.T) *respServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen resp server: %v", err)
+	}
+	server := &respServer{listener: listener, data: make(map[string]respEntry)}
+	go server.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *respServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *respServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *respServer) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	for {
+		args, err := readRespCommand(reader)
+		if err != nil {
+			return
+		}
+		s.execute(writer, args)
+		if reader.Buffered() == 0 {
+			if err = writer.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func readRespCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, errors.New("unexpected resp command")
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (s *respServer) execute(writer *bufio.Writer, args []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		_, _ = writer.WriteString("+PONG\r\n")
+	case "GET":
+		entry, ok := s.load(args[1])
+		if !ok {
+			_, _ = writer.WriteString("$-1\r\n")
+			return
+		}
+		_, _ = fmt.Fprintf(writer, "$%d\r\n%s\r\n", len(entry.value), entry.value)
+	case "SET":
+		entry := respEntry{value: args[2]}
+		for i := 3; i+1 < len(args); i += 2 {
+			n, _ := strconv.Atoi(args[i+1])
+			switch strings.ToUpper(args[i]) {
+			case "PX":
+				entry.expireAt = time.Now().Add(time.Duration(n) * time.Millisecond)
+			case "EX":
+				entry.expireAt = time.Now().Add(time.Duration(n) * time.Second)
+			}
+		}
+		s.data[args[1]] = entry
+		_, _ = writer.WriteString("+OK\r\n")
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.load(key); ok {
+				delete(s.data, key)
+				deleted++
+			}
+		}
+		_, _ = fmt.Fprintf(writer, ":%d\r\n", deleted)
+	case "PTTL":
+		entry, ok := s.load(args[1])
+		switch {
+		case !ok:
+			_, _ = writer.WriteString(":-2\r\n")
+		case entry.expireAt.IsZero():
+			_, _ = writer.WriteString(":-1\r\n")
+		default:
+			_, _ = fmt.Fprintf(writer, ":%d\r\n", time.Until(entry.expireAt).Milliseconds())
+		}
+	default:
+		_, _ = fmt.Fprintf(writer, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+func (s *respServer) load(key string) (respEntry, bool) {
+	entry, ok := s.data[key]
+	if ok && !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		delete(s.data, key)
+		return respEntry{}, false
+	}
+	return entry, ok
+}
+
+func (s *respServer) has(key string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.load(key)
+	return ok
+}
+
+func newTestRedisBucket(t *testing.T, option RedisBucketOption) (*RedisBucket, *respServer) {
+	t.Helper()
+
+	server := newRespServer(t)
+	client := redis.NewClient(&redis.Options{Addr: server.addr(), Protocol: 2, DisableIdentity: true})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisBucketByClient(client, option), server
+}
+
+func TestRedisBucketPutGetEvict(t *testing.T) {
+	bucket, server := newTestRedisBucket(t, RedisBucketOption{KeyPrefix: "app:"})
+	key := NewCacheKey("user:%d")
+
+	if _, err := bucket.GetBytes(key, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss for nil reply, got %v", err)
+	}
+	if err := bucket.PutBytes(key, []byte("redis"), 1); err != nil {
+		t.Fatalf("put redis: %v", err)
+	}
+	if !server.has("app:user:1") {
+		t.Fatal("expected key prefix to be applied")
+	}
+	got, err := bucket.GetBytes(key, 1)
+	if err != nil || string(got) != "redis" {
+		t.Fatalf("unexpected redis value %q, %v", got, err)
+	}
+	if err = bucket.Evict(key, 1); err != nil {
+		t.Fatalf("evict redis: %v", err)
+	}
+	if _, err = bucket.GetBytes(key, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after evict, got %v", err)
+	}
+}
+
+func TestRedisBucketTTL(t *testing.T) {
+	bucket, _ := newTestRedisBucket(t, RedisBucketOption{})
+	key := NewCacheKey("ttl:%s")
+
+	if err := bucket.PutBytesWithTTL(key, []byte("short"), 30*time.Millisecond, "short"); err != nil {
+		t.Fatalf("put with ttl: %v", err)
+	}
+	if ttl, err := bucket.TTL(key, "short"); err != nil || ttl <= 0 || ttl > 30*time.Millisecond {
+		t.Fatalf("unexpected ttl %v, %v", ttl, err)
+	}
+	if err := bucket.PutBytes(key, []byte("plain"), "plain"); err != nil {
+		t.Fatalf("put plain: %v", err)
+	}
+	if ttl, err := bucket.TTL(key, "plain"); err != nil || ttl != 0 {
+		t.Fatalf("expected zero ttl for plain entry, got %v, %v", ttl, err)
+	}
+	if _, err := bucket.TTL(key, "missing"); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss for missing ttl, got %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := bucket.GetBytes(key, "short"); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after ttl, got %v", err)
+	}
+}
+
+func TestRedisBucketBatch(t *testing.T) {
+	bucket, _ := newTestRedisBucket(t, RedisBucketOption{KeyPrefix: "batch:"})
+
+	if err := bucket.PutBytesBatch([]string{"a", "b", "empty"}, [][]byte{[]byte("1"), []byte("2"), {}}); err != nil {
+		t.Fatalf("put batch: %v", err)
+	}
+	values, err := bucket.GetBytesBatch([]string{"a", "missing", "b", "empty"})
+	if err != nil {
+		t.Fatalf("get batch: %v", err)
+	}
+	if string(values[0]) != "1" || values[1] != nil || string(values[2]) != "2" || values[3] == nil || len(values[3]) != 0 {
+		t.Fatalf("unexpected batch values: %q", values)
+	}
+
+	if err = bucket.EvictBatch([]string{"a", "b"}); err != nil {
+		t.Fatalf("evict batch: %v", err)
+	}
+	if values, err = bucket.GetBytesBatch([]string{"a", "b"}); err != nil || values[0] != nil || values[1] != nil {
+		t.Fatalf("expected misses after evict batch, got %q, %v", values, err)
+	}
+	if err = bucket.PutBytesBatch([]string{"a"}, nil); !errors.Is(err, toolkitError.ErrBatchSizeMismatch) {
+		t.Fatalf("expected ErrBatchSizeMismatch, got %v", err)
+	}
+}
+
+func TestRedisBucketWithCacheManager(t *testing.T) {
+	bucket, _ := newTestRedisBucket(t, RedisBucketOption{})
+	bucketName := NewBucketName("redis")
+	manager := NewCacheManager().AddBucket(bucketName, bucket)
+	key := NewCacheKey("user:%d")
+	want := testUser{Name: "Redis", Sex: 1}
+
+	if err := manager.PutWithTTL(bucketName, key, want, time.Minute, 1); err != nil {
+		t.Fatalf("put cache: %v", err)
+	}
+	var got testUser
+	if err := manager.Get(bucketName, key, &got, 1); err != nil || got != want {
+		t.Fatalf("unexpected cache value %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,251 @@
+package caching
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+// 快照格式：
+//
+//	header: magic(4) | version(1) | snapshotAt UnixNano(8)
+//	record: 0x01 | keyLen uvarint | key | dataLen uvarint | data | ttl varint
+//	footer: 0x00 | recordCount uvarint
+//
+// ttl 为生成快照时的剩余过期时间（纳秒），0 表示未单独设置过期时间
+const (
+	snapshotVersion  byte = 1
+	snapshotRecord   byte = 0x01
+	snapshotEnd      byte = 0x00
+	snapshotMaxField      = 1 << 30
+)
+
+var snapshotMagic = [4]byte{'T', 'K', 'C', 'S'}
+
+// WarmUpLoader 预热时用于加载单个 key 对应数据的函数，参数为格式化 key 使用的 keyAppend
+type WarmUpLoader func(keyAppend []interface{}) (any, error)
+
+// Snapshot 将缓存桶中所有未过期的缓存项写入 w，返回写入的缓存项数量，缓存桶需要实现 IterableCacheBucket
+func (c *CacheManager) Snapshot(bucketName BucketName, w io.Writer) (int, error) {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return 0, toolkitError.ErrBadBucketName
+	}
+	iterable, ok := bucket.(IterableCacheBucket)
+	if !ok {
+		return 0, toolkitError.ErrBucketNotIterable
+	}
+
+	writer := bufio.NewWriter(w)
+	header := make([]byte, 0, len(snapshotMagic)+9)
+	header = append(header, snapshotMagic[:]...)
+	header = append(header, snapshotVersion)
+	header = binary.BigEndian.AppendUint64(header, uint64(time.Now().UnixNano()))
+	if _, err := writer.Write(header); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var writeErr error
+	err := iterable.Range(func(rawKey string, data []byte, ttl time.Duration) bool {
+		record := make([]byte, 0, 1+len(rawKey)+len(data)+3*binary.MaxVarintLen64)
+		record = append(record, snapshotRecord)
+		record = binary.AppendUvarint(record, uint64(len(rawKey)))
+		record = append(record, rawKey...)
+		record = binary.AppendUvarint(record, uint64(len(data)))
+		record = append(record, data...)
+		record = binary.AppendVarint(record, int64(ttl))
+		if _, writeErr = writer.Write(record); writeErr != nil {
+			return false
+		}
+		count++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if writeErr != nil {
+		return 0, writeErr
+	}
+	footer := binary.AppendUvarint([]byte{snapshotEnd}, uint64(count))
+	if _, err = writer.Write(footer); err != nil {
+		return 0, err
+	}
+	return count, writer.Flush()
+}
+
+// snapshotEntry 快照中的单个缓存项，ttl 已扣除生成快照后经过的时间
+type snapshotEntry struct {
+	rawKey string
+	data   []byte
+	ttl    time.Duration
+}
+
+// Restore 从 r 读取快照并写入缓存桶，返回写入的缓存项数量
+// 完整读取并校验快照后才会写入缓存桶，快照损坏或被截断时返回 ErrInvalidSnapshot 且不写入任何缓存项
+// 缓存项的剩余过期时间会扣除生成快照后经过的时间，已过期的缓存项将被跳过
+// 缓存桶未实现 TTLCacheBucket 时，缓存项按缓存桶默认的过期策略写入
+func (c *CacheManager) Restore(bucketName BucketName, r io.Reader) (int, error) {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return 0, toolkitError.ErrBadBucketName
+	}
+	ttlBucket, _ := bucket.(TTLCacheBucket)
+
+	entries, err := readSnapshot(bufio.NewReader(r))
+	if err != nil {
+		return 0, err
+	}
+	restored := 0
+	for _, entry := range entries {
+		key := NewCacheKey(entry.rawKey)
+		if entry.ttl > 0 && ttlBucket != nil {
+			err = ttlBucket.PutBytesWithTTL(key, entry.data, entry.ttl)
+		} else {
+			err = bucket.PutBytes(key, entry.data)
+		}
+		if err != nil {
+			return restored, err
+		}
+		c.negatives.delete(loadFlightKey(bucketName, entry.rawKey))
+		c.record(bucketName, metricPut, 1, nil)
+		restored++
+	}
+	return restored, nil
+}
+
+// readSnapshot 读取并校验完整的快照，返回未过期的缓存项
+func readSnapshot(reader *bufio.Reader) ([]snapshotEntry, error) {
+	header := make([]byte, len(snapshotMagic)+9)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, toolkitError.ErrInvalidSnapshot
+	}
+	if [4]byte(header[:4]) != snapshotMagic {
+		return nil, toolkitError.ErrInvalidSnapshot
+	}
+	if header[4] != snapshotVersion {
+		return nil, toolkitError.ErrUnsupportedSnapshotVersion
+	}
+	elapsed := time.Since(time.Unix(0, int64(binary.BigEndian.Uint64(header[5:]))))
+
+	count := 0
+	var entries []snapshotEntry
+	for {
+		flag, err := reader.ReadByte()
+		if err != nil {
+			return nil, toolkitError.ErrInvalidSnapshot
+		}
+		if flag == snapshotEnd {
+			total, err := binary.ReadUvarint(reader)
+			if err != nil || total != uint64(count) {
+				return nil, toolkitError.ErrInvalidSnapshot
+			}
+			return entries, nil
+		}
+		if flag != snapshotRecord {
+			return nil, toolkitError.ErrInvalidSnapshot
+		}
+		rawKey, err := readSnapshotField(reader)
+		if err != nil {
+			return nil, err
+		}
+		data, err := readSnapshotField(reader)
+		if err != nil {
+			return nil, err
+		}
+		ttlNanos, err := binary.ReadVarint(reader)
+		if err != nil {
+			return nil, toolkitError.ErrInvalidSnapshot
+		}
+		count++
+
+		ttl := time.Duration(ttlNanos)
+		if ttl > 0 {
+			if ttl -= elapsed; ttl <= 0 {
+				continue
+			}
+		}
+		entries = append(entries, snapshotEntry{rawKey: string(rawKey), data: data, ttl: ttl})
+	}
+}
+
+// SnapshotToFile 将缓存桶的快照写入文件，先写入临时文件再替换，避免留下不完整的快照
+func (c *CacheManager) SnapshotToFile(bucketName BucketName, path string) (int, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	tmpPath := file.Name()
+	count, err := c.Snapshot(bucketName, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return 0, err
+	}
+	return count, nil
+}
+
+// RestoreFromFile 从快照文件恢复缓存桶，文件不存在时返回 os.ErrNotExist
+func (c *CacheManager) RestoreFromFile(bucketName BucketName, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+	return c.Restore(bucketName, file)
+}
+
+// WarmUp 预热缓存，keyAppends 中的每一项用于格式化一个 key，只为尚未缓存的 key 调用 loader
+// 单个 key 加载失败不会中断预热，返回成功写入的数量以及所有加载错误
+func (c *CacheManager) WarmUp(bucketName BucketName, key CacheKey, keyAppends [][]interface{}, loader WarmUpLoader) (int, error) {
+	cached, err := c.GetMany(bucketName, key, keyAppends)
+	if err != nil {
+		return 0, err
+	}
+	missAppends := make([][]interface{}, 0)
+	values := make([]any, 0)
+	var loadErrs []error
+	for _, index := range cached.Misses() {
+		start := time.Now()
+		value, err := loader(keyAppends[index])
+		c.recordLoad(bucketName, time.Since(start), err)
+		if err != nil {
+			loadErrs = append(loadErrs, err)
+			continue
+		}
+		missAppends = append(missAppends, keyAppends[index])
+		values = append(values, value)
+	}
+	if len(values) > 0 {
+		if err = c.PutMany(bucketName, key, missAppends, values); err != nil {
+			return 0, err
+		}
+	}
+	return len(values), errors.Join(loadErrs...)
+}
+
+func readSnapshotField(reader *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(reader)
+	if err != nil || size > snapshotMaxField {
+		return nil, toolkitError.ErrInvalidSnapshot
+	}
+	field := make([]byte, size)
+	if _, err = io.ReadFull(reader, field); err != nil {
+		return nil, toolkitError.ErrInvalidSnapshot
+	}
+	return field, nil
+}
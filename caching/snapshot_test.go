@@ -0,0 +1,131 @@
+package caching
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestCacheManagerSnapshotRestore(t *testing.T) {
+	source, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("snapshot:%d")
+
+	if err := source.Put(bucketName, key, testUser{Name: "A"}, 1); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := source.PutWithTTL(bucketName, key, testUser{Name: "B"}, time.Minute, 2); err != nil {
+		t.Fatalf("put with ttl: %v", err)
+	}
+	if err := source.PutWithTTL(bucketName, key, testUser{Name: "C"}, 10*time.Millisecond, 3); err != nil {
+		t.Fatalf("put short ttl: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	var buf bytes.Buffer
+	count, err := source.Snapshot(bucketName, &buf)
+	if err != nil || count != 2 {
+		t.Fatalf("unexpected snapshot result %d, %v", count, err)
+	}
+
+	target := NewCacheManager().AddBucket(bucketName, NewSimpleMemoryBucket(0, 0))
+	if count, err = target.Restore(bucketName, &buf); err != nil || count != 2 {
+		t.Fatalf("unexpected restore result %d, %v", count, err)
+	}
+	var got testUser
+	if err = target.Get(bucketName, key, &got, 1); err != nil || got.Name != "A" {
+		t.Fatalf("unexpected restored value %+v, %v", got, err)
+	}
+	if ttl, err := target.TTL(bucketName, key, 1); err != nil || ttl != 0 {
+		t.Fatalf("expected no ttl for restored entry, got %v, %v", ttl, err)
+	}
+	if ttl, err := target.TTL(bucketName, key, 2); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("unexpected restored ttl %v, %v", ttl, err)
+	}
+	if _, err = target.GetBytes(bucketName, key, 3); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected expired entry to be skipped, got %v", err)
+	}
+}
+
+func TestCacheManagerSnapshotFile(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	path := filepath.Join(t.TempDir(), "bucket.snapshot")
+	if err := manager.PutBytes(bucketName, NewCacheKey("file"), []byte("value")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if count, err := manager.SnapshotToFile(bucketName, path); err != nil || count != 1 {
+		t.Fatalf("unexpected snapshot file result %d, %v", count, err)
+	}
+
+	restored, restoredName := newTestCacheManager(t)
+	if count, err := restored.RestoreFromFile(restoredName, path); err != nil || count != 1 {
+		t.Fatalf("unexpected restore file result %d, %v", count, err)
+	}
+	if bs, err := restored.GetBytes(restoredName, NewCacheKey("file")); err != nil || string(bs) != "value" {
+		t.Fatalf("unexpected restored bytes %q, %v", bs, err)
+	}
+}
+
+func TestCacheManagerRestoreInvalidSnapshot(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	if err := manager.PutBytes(bucketName, NewCacheKey("a"), []byte("1")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := manager.Snapshot(bucketName, &buf); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	data := buf.Bytes()
+
+	if _, err := manager.Restore(bucketName, bytes.NewReader([]byte("nope"))); !errors.Is(err, toolkitError.ErrInvalidSnapshot) {
+		t.Fatalf("expected ErrInvalidSnapshot for bad magic, got %v", err)
+	}
+	if _, err := manager.Restore(bucketName, bytes.NewReader(data[:len(data)-2])); !errors.Is(err, toolkitError.ErrInvalidSnapshot) {
+		t.Fatalf("expected ErrInvalidSnapshot for truncated data, got %v", err)
+	}
+	// 截断的快照不会写入任何缓存项
+	target, targetName := newTestCacheManager(t)
+	if count, err := target.Restore(targetName, bytes.NewReader(data[:len(data)-2])); !errors.Is(err, toolkitError.ErrInvalidSnapshot) || count != 0 {
+		t.Fatalf("expected ErrInvalidSnapshot without restored entries, got %d, %v", count, err)
+	}
+	if _, err := target.GetBytes(targetName, NewCacheKey("a")); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected truncated snapshot to restore nothing, got %v", err)
+	}
+	versioned := append([]byte(nil), data...)
+	versioned[4] = 99
+	if _, err := manager.Restore(bucketName, bytes.NewReader(versioned)); !errors.Is(err, toolkitError.ErrUnsupportedSnapshotVersion) {
+		t.Fatalf("expected ErrUnsupportedSnapshotVersion, got %v", err)
+	}
+}
+
+func TestCacheManagerWarmUp(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("warm:%d")
+	loadErr := errors.New("load failed")
+	if err := manager.Put(bucketName, key, testUser{Name: "cached"}, 1); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	loaded := make([]int, 0)
+	count, err := manager.WarmUp(bucketName, key, [][]interface{}{{1}, {2}, {3}}, func(keyAppend []interface{}) (any, error) {
+		id := keyAppend[0].(int)
+		loaded = append(loaded, id)
+		if id == 3 {
+			return nil, loadErr
+		}
+		return testUser{Name: "loaded"}, nil
+	})
+	if count != 1 || !errors.Is(err, loadErr) {
+		t.Fatalf("unexpected warm up result %d, %v", count, err)
+	}
+	if len(loaded) != 2 || loaded[0] != 2 || loaded[1] != 3 {
+		t.Fatalf("expected only missing keys to be loaded, got %v", loaded)
+	}
+	var got testUser
+	if err = manager.Get(bucketName, key, &got, 2); err != nil || got.Name != "loaded" {
+		t.Fatalf("unexpected warmed value %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,219 @@
+package caching
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+// BucketStats 缓存桶的统计快照
+type BucketStats struct {
+	// Hits 命中次数
+	Hits uint64
+	// StaleHits 命中软过期缓存值的次数，已计入 Hits
+	StaleHits uint64
+	// Misses 未命中次数
+	Misses uint64
+	// Puts 写入次数
+	Puts uint64
+	// Evictions 主动清除次数
+	Evictions uint64
+	// DecodeErrors 解码失败次数
+	DecodeErrors uint64
+	// Loads 读穿加载时调用 Loader 的次数
+	Loads uint64
+	// LoadErrors Loader 返回错误的次数
+	LoadErrors uint64
+	// LoadDuration Loader 的累计耗时
+	LoadDuration time.Duration
+}
+
+// HitRatio 获取命中率，没有读取记录时返回 0
+func (s BucketStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// AverageLoadDuration 获取 Loader 的平均耗时
+func (s BucketStats) AverageLoadDuration() time.Duration {
+	if s.Loads == 0 {
+		return 0
+	}
+	return s.LoadDuration / time.Duration(s.Loads)
+}
+
+// MetricsObserver 缓存指标观察者，可用于对接 Prometheus 等监控系统
+// 方法会在缓存操作的调用链路中同步执行，实现需要并发安全并尽快返回
+type MetricsObserver interface {
+	// OnHit 缓存命中
+	OnHit(bucketName BucketName)
+	// OnStaleHit 读穿加载时命中软过期的缓存值，同时会触发 OnHit
+	OnStaleHit(bucketName BucketName)
+	// OnMiss 缓存未命中
+	OnMiss(bucketName BucketName)
+	// OnPut 写入缓存
+	OnPut(bucketName BucketName)
+	// OnEvict 主动清除缓存
+	OnEvict(bucketName BucketName)
+	// OnDecodeError 缓存值解码失败
+	OnDecodeError(bucketName BucketName, err error)
+	// OnLoad 读穿加载时调用 Loader 完成，err 为 Loader 返回的错误
+	OnLoad(bucketName BucketName, duration time.Duration, err error)
+}
+
+const (
+	metricHit metricEvent = iota
+	metricMiss
+	metricPut
+	metricEvict
+	metricDecodeError
+	metricStaleHit
+)
+
+type metricEvent uint8
+
+type bucketCounters struct {
+	hits         atomic.Uint64
+	staleHits    atomic.Uint64
+	misses       atomic.Uint64
+	puts         atomic.Uint64
+	evictions    atomic.Uint64
+	decodeErrors atomic.Uint64
+	loads        atomic.Uint64
+	loadErrors   atomic.Uint64
+	loadNanos    atomic.Int64
+}
+
+func (b *bucketCounters) snapshot() BucketStats {
+	return BucketStats{
+		Hits:         b.hits.Load(),
+		StaleHits:    b.staleHits.Load(),
+		Misses:       b.misses.Load(),
+		Puts:         b.puts.Load(),
+		Evictions:    b.evictions.Load(),
+		DecodeErrors: b.decodeErrors.Load(),
+		Loads:        b.loads.Load(),
+		LoadErrors:   b.loadErrors.Load(),
+		LoadDuration: time.Duration(b.loadNanos.Load()),
+	}
+}
+
+// SetMetricsObserver 设置缓存指标观察者，传入 nil 时取消观察
+func (c *CacheManager) SetMetricsObserver(observer MetricsObserver) *CacheManager {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.observer = observer
+	return c
+}
+
+// Stats 获取指定缓存桶的统计快照
+func (c *CacheManager) Stats(bucketName BucketName) (BucketStats, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	counters, ok := c.counters[bucketName]
+	if !ok {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return BucketStats{}, toolkitError.ErrBadBucketName
+	}
+	return counters.snapshot(), nil
+}
+
+func (c *CacheManager) metrics(bucketName BucketName) (*bucketCounters, MetricsObserver) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.counters[bucketName], c.observer
+}
+
+// record 记录一次缓存事件，count 为事件次数
+func (c *CacheManager) record(bucketName BucketName, event metricEvent, count int, err error) {
+	counters, observer := c.metrics(bucketName)
+	if counters == nil || count <= 0 {
+		return
+	}
+	switch event {
+	case metricHit:
+		counters.hits.Add(uint64(count))
+	case metricMiss:
+		counters.misses.Add(uint64(count))
+	case metricPut:
+		counters.puts.Add(uint64(count))
+	case metricEvict:
+		counters.evictions.Add(uint64(count))
+	case metricDecodeError:
+		counters.decodeErrors.Add(uint64(count))
+	case metricStaleHit:
+		counters.staleHits.Add(uint64(count))
+	}
+	if observer == nil {
+		return
+	}
+	for i := 0; i < count; i++ {
+		switch event {
+		case metricHit:
+			observer.OnHit(bucketName)
+		case metricStaleHit:
+			observer.OnStaleHit(bucketName)
+		case metricMiss:
+			observer.OnMiss(bucketName)
+		case metricPut:
+			observer.OnPut(bucketName)
+		case metricEvict:
+			observer.OnEvict(bucketName)
+		case metricDecodeError:
+			observer.OnDecodeError(bucketName, err)
+		}
+	}
+}
+
+// recordGet 根据读取结果记录命中或未命中
+func (c *CacheManager) recordGet(bucketName BucketName, err error) {
+	switch {
+	case err == nil:
+		c.record(bucketName, metricHit, 1, nil)
+	case errors.Is(err, toolkitError.ErrCacheMiss):
+		c.record(bucketName, metricMiss, 1, nil)
+	}
+}
+
+// recordWrite 写入或清除成功时记录对应事件
+func (c *CacheManager) recordWrite(bucketName BucketName, event metricEvent, err error) {
+	if err == nil {
+		c.record(bucketName, event, 1, nil)
+	}
+}
+
+func (c *CacheManager) recordLoad(bucketName BucketName, duration time.Duration, err error) {
+	counters, observer := c.metrics(bucketName)
+	if counters == nil {
+		return
+	}
+	counters.loads.Add(1)
+	counters.loadNanos.Add(int64(duration))
+	if err != nil {
+		counters.loadErrors.Add(1)
+	}
+	if observer != nil {
+		observer.OnLoad(bucketName, duration, err)
+	}
+}
+
+// decode 使用 Codec 解码原始 key 对应的缓存值，并记录解码失败
+func (c *CacheManager) decode(bucketName BucketName, rawKey string, bs []byte, result any) error {
+	if raw, _, _, ok := parseFreshness(bs); ok {
+		bs = raw
+	}
+	if err := decodeWithKey(c.codec, rawKey, bs, result); err != nil {
+		c.record(bucketName, metricDecodeError, 1, err)
+		return err
+	}
+	return nil
+}
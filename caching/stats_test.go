@@ -0,0 +1,173 @@
+package caching
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+type countingObserver struct {
+	lock         sync.Mutex
+	hits         int
+	staleHits    int
+	misses       int
+	puts         int
+	evicts       int
+	decodeErrors int
+	loads        int
+	loadErrors   int
+}
+
+func (o *countingObserver) OnHit(BucketName) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.hits++
+}
+
+func (o *countingObserver) OnStaleHit(BucketName) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.staleHits++
+}
+
+func (o *countingObserver) OnMiss(BucketName) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.misses++
+}
+
+func (o *countingObserver) OnPut(BucketName) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.puts++
+}
+
+func (o *countingObserver) OnEvict(BucketName) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.evicts++
+}
+
+func (o *countingObserver) OnDecodeError(BucketName, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.decodeErrors++
+}
+
+func (o *countingObserver) OnLoad(_ BucketName, _ time.Duration, err error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.loads++
+	if err != nil {
+		o.loadErrors++
+	}
+}
+
+func TestCacheManagerStats(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	observer := &countingObserver{}
+	manager.SetMetricsObserver(observer)
+	key := NewCacheKey("stats:%d")
+
+	var got testUser
+	if err := manager.Get(bucketName, key, &got, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss, got %v", err)
+	}
+	if err := manager.Put(bucketName, key, testUser{Name: "S"}, 1); err != nil {
+		t.Fatalf("put cache: %v", err)
+	}
+	if err := manager.Get(bucketName, key, &got, 1); err != nil {
+		t.Fatalf("get cache: %v", err)
+	}
+	if err := manager.PutBytes(bucketName, key, []byte("broken"), 2); err != nil {
+		t.Fatalf("put bytes: %v", err)
+	}
+	if err := manager.Get(bucketName, key, &got, 2); err == nil {
+		t.Fatal("expected decode error")
+	}
+	if err := manager.Evict(bucketName, key, 1); err != nil {
+		t.Fatalf("evict cache: %v", err)
+	}
+
+	stats, err := manager.Stats(bucketName)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Puts != 2 || stats.Evictions != 1 || stats.DecodeErrors != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Fatalf("unexpected hit ratio %v", ratio)
+	}
+	if observer.hits != 2 || observer.misses != 1 || observer.puts != 2 || observer.evicts != 1 || observer.decodeErrors != 1 {
+		t.Fatalf("unexpected observer counts %+v", observer)
+	}
+}
+
+func TestCacheManagerStatsLoad(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	observer := &countingObserver{}
+	manager.SetMetricsObserver(observer)
+	key := NewCacheKey("load:%d")
+	loadErr := errors.New("load failed")
+
+	var got testUser
+	if err := manager.GetOrLoad(bucketName, key, &got, func() (any, error) {
+		time.Sleep(5 * time.Millisecond)
+		return testUser{Name: "L"}, nil
+	}, 1); err != nil {
+		t.Fatalf("get or load: %v", err)
+	}
+	if err := manager.GetOrLoad(bucketName, key, &got, func() (any, error) {
+		return nil, loadErr
+	}, 2); !errors.Is(err, loadErr) {
+		t.Fatalf("expected load error, got %v", err)
+	}
+
+	stats, err := manager.Stats(bucketName)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.Loads != 2 || stats.LoadErrors != 1 || stats.Puts != 1 || stats.Misses != 2 {
+		t.Fatalf("unexpected load stats %+v", stats)
+	}
+	if stats.LoadDuration < 5*time.Millisecond || stats.AverageLoadDuration() <= 0 {
+		t.Fatalf("unexpected load duration %v", stats.LoadDuration)
+	}
+	if observer.loads != 2 || observer.loadErrors != 1 {
+		t.Fatalf("unexpected observer loads %d, errors %d", observer.loads, observer.loadErrors)
+	}
+}
+
+func TestCacheManagerStatsBatch(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("batch:%d")
+
+	if err := manager.PutMany(bucketName, key, [][]interface{}{{1}, {2}}, []any{testUser{Name: "A"}, testUser{Name: "B"}}); err != nil {
+		t.Fatalf("put many: %v", err)
+	}
+	if _, err := manager.GetMany(bucketName, key, [][]interface{}{{1}, {2}, {3}}); err != nil {
+		t.Fatalf("get many: %v", err)
+	}
+	if err := manager.EvictMany(bucketName, key, [][]interface{}{{1}, {2}}); err != nil {
+		t.Fatalf("evict many: %v", err)
+	}
+
+	stats, err := manager.Stats(bucketName)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.Puts != 2 || stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 2 {
+		t.Fatalf("unexpected batch stats %+v", stats)
+	}
+}
+
+func TestCacheManagerStatsBadBucketName(t *testing.T) {
+	manager := NewCacheManager()
+	if _, err := manager.Stats(NewBucketName("missing")); !errors.Is(err, toolkitError.ErrBadBucketName) {
+		t.Fatalf("expected ErrBadBucketName, got %v", err)
+	}
+}
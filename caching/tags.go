@@ -0,0 +1,193 @@
+package caching
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+// tagIndex 记录单个缓存桶内标签与原始 key 的对应关系
+// 索引只保存在当前进程中，缓存项因过期或容量被缓存桶自动清除时不会同步清理，
+// 这些残留的 key 会在下一次 EvictByTag 时一并清除
+type tagIndex struct {
+	lock    sync.Mutex
+	keys    map[string]map[string]struct{}
+	rawTags map[string]map[string]struct{}
+}
+
+func newTagIndex() *tagIndex {
+	return &tagIndex{
+		keys:    make(map[string]map[string]struct{}),
+		rawTags: make(map[string]map[string]struct{}),
+	}
+}
+
+func (t *tagIndex) add(rawKey string, tags []string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if t.keys[tag] == nil {
+			t.keys[tag] = make(map[string]struct{})
+		}
+		t.keys[tag][rawKey] = struct{}{}
+		if t.rawTags[rawKey] == nil {
+			t.rawTags[rawKey] = make(map[string]struct{})
+		}
+		t.rawTags[rawKey][tag] = struct{}{}
+	}
+}
+
+// keysOf 获取标签下的所有原始 key，不修改索引，清除成功后再通过 remove 移除
+func (t *tagIndex) keysOf(tag string) []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	rawKeys := make([]string, 0, len(t.keys[tag]))
+	for rawKey := range t.keys[tag] {
+		rawKeys = append(rawKeys, rawKey)
+	}
+	return rawKeys
+}
+
+func (t *tagIndex) remove(rawKeys ...string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, rawKey := range rawKeys {
+		t.removeLocked(rawKey)
+	}
+}
+
+func (t *tagIndex) removeLocked(rawKey string) {
+	for tag := range t.rawTags[rawKey] {
+		delete(t.keys[tag], rawKey)
+		if len(t.keys[tag]) == 0 {
+			delete(t.keys, tag)
+		}
+	}
+	delete(t.rawTags, rawKey)
+}
+
+func (c *CacheManager) tagIndex(bucketName BucketName) *tagIndex {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.tags[bucketName]
+}
+
+// untag 将已清除的原始 key 从标签索引中移除
+func (c *CacheManager) untag(bucketName BucketName, rawKeys ...string) {
+	if index := c.tagIndex(bucketName); index != nil {
+		index.remove(rawKeys...)
+	}
+}
+
+// afterPut 记录写入结果，写入成功时移除 key 原有的标签，新写入的值不再属于之前的标签
+func (c *CacheManager) afterPut(bucketName BucketName, rawKey string, err error) {
+	if err == nil {
+		c.untag(bucketName, rawKey)
+	}
+	c.recordWrite(bucketName, metricPut, err)
+}
+
+// PutWithTags 缓存数据并为其添加标签，之后可通过 EvictByTag 一次清除同一标签下的所有缓存
+// 已存在的 key 原有的标签会被替换为 tags
+func (c *CacheManager) PutWithTags(bucketName BucketName, key CacheKey, data any, tags []string, keyAppend ...interface{}) error {
+	if err := c.Put(bucketName, key, data, keyAppend...); err != nil {
+		return err
+	}
+	return c.Tag(bucketName, key, tags, keyAppend...)
+}
+
+// Tag 为已写入的缓存添加标签，可与 PutWithTTL、GetOrLoad 等写入方式配合使用
+// 通过 Put、PutWithTTL、PutMany 等方法重新写入 key 时会移除其所有标签，需要在写入后重新添加
+func (c *CacheManager) Tag(bucketName BucketName, key CacheKey, tags []string, keyAppend ...interface{}) error {
+	index := c.tagIndex(bucketName)
+	if index == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return toolkitError.ErrBadBucketName
+	}
+	index.add(key.RawKeyString(keyAppend...), tags)
+	return nil
+}
+
+// EvictByTag 清除标签下的所有缓存，返回清除的 key 数量
+func (c *CacheManager) EvictByTag(bucketName BucketName, tag string) (int, error) {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return 0, toolkitError.ErrBadBucketName
+	}
+	rawKeys := c.tagIndex(bucketName).keysOf(tag)
+	count, err := c.evictRawKeys(bucketName, bucket, rawKeys)
+	if err != nil {
+		return count, err
+	}
+	// 其他节点按各自的标签索引清除
+	c.publishQuietly(InvalidationEvent{Bucket: bucketName, Kind: InvalidationTag, Tag: tag})
+	return count, nil
+}
+
+// EvictByPrefix 清除原始 key 以 prefix 开头的所有缓存，返回清除的 key 数量，缓存桶需要实现 IterableCacheBucket
+func (c *CacheManager) EvictByPrefix(bucketName BucketName, prefix string) (int, error) {
+	bucket := c.GetBucket(bucketName)
+	if bucket == nil {
+		logger.Logrus().Errorln("caching: bad bucketName", bucketName)
+		return 0, toolkitError.ErrBadBucketName
+	}
+	iterable, ok := bucket.(IterableCacheBucket)
+	if !ok {
+		return 0, toolkitError.ErrBucketNotIterable
+	}
+	// 先收集 key 再清除，避免在遍历过程中修改缓存桶
+	rawKeys := make([]string, 0)
+	err := iterable.Range(func(rawKey string, _ []byte, _ time.Duration) bool {
+		if strings.HasPrefix(rawKey, prefix) {
+			rawKeys = append(rawKeys, rawKey)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	count, err := c.evictRawKeys(bucketName, bucket, rawKeys)
+	if err != nil {
+		return count, err
+	}
+	c.publishQuietly(InvalidationEvent{Bucket: bucketName, Kind: InvalidationPrefix, Prefix: prefix})
+	return count, nil
+}
+
+// evictRawKeys 清除原始 key 并返回清除的数量，只有清除成功的 key 才会从标签索引中移除，失败后可重试
+func (c *CacheManager) evictRawKeys(bucketName BucketName, bucket CacheBucket, rawKeys []string) (int, error) {
+	if len(rawKeys) == 0 {
+		return 0, nil
+	}
+	for _, rawKey := range rawKeys {
+		c.negatives.delete(loadFlightKey(bucketName, rawKey))
+	}
+	if batchBucket, ok := bucket.(BatchCacheBucket); ok {
+		if err := batchBucket.EvictBatch(rawKeys); err != nil {
+			return 0, err
+		}
+		c.untag(bucketName, rawKeys...)
+		c.record(bucketName, metricEvict, len(rawKeys), nil)
+		return len(rawKeys), nil
+	}
+	for i, rawKey := range rawKeys {
+		// 标签索引中残留的 key 可能已被缓存桶自动清除
+		if err := bucket.Evict(NewCacheKey(rawKey)); err != nil && !isEntryNotFound(err) {
+			return i, err
+		}
+		c.untag(bucketName, rawKey)
+		c.record(bucketName, metricEvict, 1, nil)
+	}
+	return len(rawKeys), nil
+}
@@ -0,0 +1,188 @@
+package caching
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestCacheManagerEvictByTag(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	profile := NewCacheKey("user:profile:%d")
+	orders := NewCacheKey("user:orders:%d")
+
+	if err := manager.PutWithTags(bucketName, profile, testUser{Name: "T"}, []string{"user:1"}, 1); err != nil {
+		t.Fatalf("put profile: %v", err)
+	}
+	if err := manager.PutWithTTL(bucketName, orders, []int{1, 2}, time.Minute, 1); err != nil {
+		t.Fatalf("put orders: %v", err)
+	}
+	if err := manager.Tag(bucketName, orders, []string{"user:1", "orders"}, 1); err != nil {
+		t.Fatalf("tag orders: %v", err)
+	}
+	if err := manager.PutWithTags(bucketName, profile, testUser{Name: "O"}, []string{"user:2"}, 2); err != nil {
+		t.Fatalf("put other profile: %v", err)
+	}
+
+	count, err := manager.EvictByTag(bucketName, "user:1")
+	if err != nil || count != 2 {
+		t.Fatalf("unexpected evict by tag result %d, %v", count, err)
+	}
+	if _, err = manager.GetBytes(bucketName, profile, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected profile to be evicted, got %v", err)
+	}
+	if _, err = manager.GetBytes(bucketName, orders, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected orders to be evicted, got %v", err)
+	}
+	if _, err = manager.GetBytes(bucketName, profile, 2); err != nil {
+		t.Fatalf("expected other profile to remain, got %v", err)
+	}
+	// 随 user:1 清除的 key 不再保留在其他标签下
+	if count, err = manager.EvictByTag(bucketName, "orders"); err != nil || count != 0 {
+		t.Fatalf("expected orders tag to be empty, got %d, %v", count, err)
+	}
+}
+
+func TestCacheManagerPutReplacesTags(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("retag:%d")
+
+	if err := manager.PutWithTags(bucketName, key, testUser{Name: "A"}, []string{"old"}, 1); err != nil {
+		t.Fatalf("put tagged: %v", err)
+	}
+	if err := manager.PutWithTags(bucketName, key, testUser{Name: "B"}, []string{"new"}, 1); err != nil {
+		t.Fatalf("retag: %v", err)
+	}
+	// 重新添加标签后不再属于旧标签
+	if count, err := manager.EvictByTag(bucketName, "old"); err != nil || count != 0 {
+		t.Fatalf("expected old tag to be empty, got %d, %v", count, err)
+	}
+	if err := manager.Put(bucketName, key, testUser{Name: "C"}, 1); err != nil {
+		t.Fatalf("put untagged: %v", err)
+	}
+	// 不带标签重新写入后不再属于任何标签
+	if count, err := manager.EvictByTag(bucketName, "new"); err != nil || count != 0 {
+		t.Fatalf("expected new tag to be empty, got %d, %v", count, err)
+	}
+	var got testUser
+	if err := manager.Get(bucketName, key, &got, 1); err != nil || got.Name != "C" {
+		t.Fatalf("expected value to remain, got %+v, %v", got, err)
+	}
+	if len(manager.tagIndex(bucketName).rawTags) != 0 {
+		t.Fatalf("expected empty tag index, got %v", manager.tagIndex(bucketName).rawTags)
+	}
+}
+
+// failingEvictBucket 在 fail 为 true 时清除失败的缓存桶
+type failingEvictBucket struct {
+	CacheBucket
+	fail bool
+}
+
+func (f *failingEvictBucket) Evict(key CacheKey, keyAppend ...interface{}) error {
+	if f.fail {
+		return errors.New("evict failed")
+	}
+	return f.CacheBucket.Evict(key, keyAppend...)
+}
+
+func TestCacheManagerEvictByTagKeepsKeysOnFailure(t *testing.T) {
+	bucketName := NewBucketName("failing")
+	bucket := &failingEvictBucket{CacheBucket: newTestMemoryBucket(t, MemoryBucketOption{}), fail: true}
+	manager := NewCacheManager().AddBucket(bucketName, bucket)
+	key := NewCacheKey("tagged:%d")
+	if err := manager.PutWithTags(bucketName, key, testUser{Name: "F"}, []string{"t"}, 1); err != nil {
+		t.Fatalf("put tagged: %v", err)
+	}
+
+	if _, err := manager.EvictByTag(bucketName, "t"); err == nil {
+		t.Fatal("expected evict by tag error")
+	}
+	// 清除失败的 key 仍保留在标签索引中，可以重试
+	bucket.fail = false
+	if count, err := manager.EvictByTag(bucketName, "t"); err != nil || count != 1 {
+		t.Fatalf("unexpected retry result %d, %v", count, err)
+	}
+	if _, err := manager.GetBytes(bucketName, key, 1); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected tagged key to be evicted, got %v", err)
+	}
+}
+
+func TestCacheManagerEvictRemovesTags(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("tagged:%d")
+
+	if err := manager.PutWithTags(bucketName, key, "value", []string{"group"}, 1); err != nil {
+		t.Fatalf("put tagged: %v", err)
+	}
+	if err := manager.Evict(bucketName, key, 1); err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+	if count, err := manager.EvictByTag(bucketName, "group"); err != nil || count != 0 {
+		t.Fatalf("expected evicted key to be untagged, got %d, %v", count, err)
+	}
+}
+
+func TestCacheManagerEvictByPrefix(t *testing.T) {
+	memoryName := NewBucketName("memory")
+	manager, bigCacheName := newTestCacheManager(t)
+	manager.AddBucket(memoryName, NewSimpleMemoryBucket(0, 0))
+
+	for _, bucketName := range []BucketName{bigCacheName, memoryName} {
+		for _, rawKey := range []string{"user:1:profile", "user:1:orders", "user:10:profile", "order:1"} {
+			if err := manager.PutBytes(bucketName, NewCacheKey(rawKey), []byte(rawKey)); err != nil {
+				t.Fatalf("put %s: %v", rawKey, err)
+			}
+		}
+		count, err := manager.EvictByPrefix(bucketName, "user:1:")
+		if err != nil || count != 2 {
+			t.Fatalf("unexpected evict by prefix result on %s: %d, %v", bucketName, count, err)
+		}
+		remaining := make([]string, 0)
+		err = manager.GetBucket(bucketName).(IterableCacheBucket).Range(func(rawKey string, _ []byte, _ time.Duration) bool {
+			remaining = append(remaining, rawKey)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("range %s: %v", bucketName, err)
+		}
+		sort.Strings(remaining)
+		if len(remaining) != 2 || remaining[0] != "order:1" || remaining[1] != "user:10:profile" {
+			t.Fatalf("unexpected remaining keys on %s: %v", bucketName, remaining)
+		}
+	}
+}
+
+func TestCacheManagerEvictByPrefixNotIterable(t *testing.T) {
+	bucketName := NewBucketName("plain")
+	manager := NewCacheManager().AddBucket(bucketName, plainBucket{CacheBucket: newTestBigCache(t)})
+
+	if _, err := manager.EvictByPrefix(bucketName, "user:"); !errors.Is(err, toolkitError.ErrBucketNotIterable) {
+		t.Fatalf("expected ErrBucketNotIterable, got %v", err)
+	}
+}
+
+func TestBigCacheBucketRangeSkipsExpired(t *testing.T) {
+	bucket := newTestBigCache(t)
+	if err := bucket.PutBytesWithTTL(NewCacheKey("short"), []byte("s"), 10*time.Millisecond); err != nil {
+		t.Fatalf("put short: %v", err)
+	}
+	if err := bucket.PutBytesWithTTL(NewCacheKey("long"), []byte("l"), time.Minute); err != nil {
+		t.Fatalf("put long: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	visited := make(map[string]time.Duration)
+	if err := bucket.Range(func(rawKey string, _ []byte, ttl time.Duration) bool {
+		visited[rawKey] = ttl
+		return true
+	}); err != nil {
+		t.Fatalf("range: %v", err)
+	}
+	if _, ok := visited["short"]; ok || len(visited) != 1 || visited["long"] <= 0 {
+		t.Fatalf("unexpected range result %v", visited)
+	}
+}
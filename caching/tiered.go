@@ -0,0 +1,168 @@
+package caching
+
+import (
+	"errors"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/logger"
+)
+
+// TieredBucketOption 表示创建两级缓存桶时使用的配置
+type TieredBucketOption struct {
+	// LocalTTL 写入或回填本地缓存时使用的过期时间，本地缓存桶需要实现 TTLCacheBucket，为 0 时使用本地缓存桶默认策略
+	// 建议设置较短的时间以限制未收到失效通知时本地缓存的不一致窗口
+	LocalTTL time.Duration
+	// OnInvalidate 写入缓存后调用，用于通知其他节点通过 EvictLocal 清除其本地缓存，
+	// 可调用 CacheManager.PublishInvalidation 发送通知
+	// 清除缓存不会调用，跨节点的清除通知由 CacheManager.EnableInvalidation 统一发布，避免同一次清除重复通知
+	OnInvalidate func(rawKey string)
+}
+
+// TieredBucket 两级缓存桶，本地缓存桶作为一级缓存，共享的远程缓存桶作为二级缓存
+// 读取时依次查询本地和远程缓存，远程命中后回填本地缓存，回填的过期时间不超过远程缓存剩余的过期时间；
+// 写入和清除时同时作用于两级缓存
+type TieredBucket struct {
+	local  CacheBucket
+	remote CacheBucket
+	option TieredBucketOption
+}
+
+// NewTieredBucket 创建两级缓存桶，设置了 LocalTTL 但本地缓存桶未实现 TTLCacheBucket 时返回 ErrTTLNotSupported
+func NewTieredBucket(local, remote CacheBucket, option ...TieredBucketOption) (*TieredBucket, error) {
+	bucket := &TieredBucket{
+		local:  local,
+		remote: remote,
+	}
+	if len(option) > 0 {
+		bucket.option = option[0]
+	}
+	if bucket.option.LocalTTL > 0 {
+		if _, ok := local.(TTLCacheBucket); !ok {
+			return nil, toolkitError.ErrTTLNotSupported
+		}
+	}
+	return bucket, nil
+}
+
+// Local 获取本地缓存桶
+func (t *TieredBucket) Local() CacheBucket {
+	return t.local
+}
+
+// Remote 获取远程缓存桶
+func (t *TieredBucket) Remote() CacheBucket {
+	return t.remote
+}
+
+func (t *TieredBucket) GetBytes(key CacheKey, keyAppend ...interface{}) ([]byte, error) {
+	bs, err := t.local.GetBytes(key, keyAppend...)
+	if err == nil {
+		return bs, nil
+	}
+	if !errors.Is(err, toolkitError.ErrCacheMiss) {
+		logger.Logrus().Warningln("caching: tiered local get failed", err)
+	}
+	bs, err = t.remote.GetBytes(key, keyAppend...)
+	if err != nil {
+		return nil, err
+	}
+	// 回填的本地缓存不能比远程缓存更晚过期
+	ttl := time.Duration(0)
+	if remote, ok := t.remote.(TTLCacheBucket); ok {
+		if ttl, err = remote.TTL(key, keyAppend...); err != nil {
+			// 远程缓存可能刚刚过期或被清除，不再回填
+			return bs, nil
+		}
+	}
+	if err = t.putLocal(key, bs, t.localTTL(ttl), keyAppend...); err != nil && !errors.Is(err, toolkitError.ErrTTLNotSupported) {
+		logger.Logrus().Warningln("caching: tiered local fill failed", err)
+	}
+	return bs, nil
+}
+
+func (t *TieredBucket) PutBytes(key CacheKey, data []byte, keyAppend ...interface{}) error {
+	if err := t.remote.PutBytes(key, data, keyAppend...); err != nil {
+		return err
+	}
+	return t.afterRemotePut(key, data, t.option.LocalTTL, keyAppend...)
+}
+
+// PutBytesWithTTL 设置key对应的字节数组，并指定过期时间，远程缓存桶需要实现 TTLCacheBucket
+// 本地缓存使用 ttl 与 LocalTTL 中较短的过期时间，本地缓存桶未实现 TTLCacheBucket 时只写入远程缓存
+func (t *TieredBucket) PutBytesWithTTL(key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error {
+	remote, ok := t.remote.(TTLCacheBucket)
+	if !ok {
+		return toolkitError.ErrTTLNotSupported
+	}
+	if err := remote.PutBytesWithTTL(key, data, ttl, keyAppend...); err != nil {
+		return err
+	}
+	return t.afterRemotePut(key, data, t.localTTL(ttl), keyAppend...)
+}
+
+// TTL 获取远程缓存剩余的过期时间，远程缓存桶需要实现 TTLCacheBucket
+func (t *TieredBucket) TTL(key CacheKey, keyAppend ...interface{}) (time.Duration, error) {
+	remote, ok := t.remote.(TTLCacheBucket)
+	if !ok {
+		return 0, toolkitError.ErrTTLNotSupported
+	}
+	return remote.TTL(key, keyAppend...)
+}
+
+// Evict 清除两级缓存，本地缓存中不存在的 key 视为清除成功
+func (t *TieredBucket) Evict(key CacheKey, keyAppend ...interface{}) error {
+	if err := t.remote.Evict(key, keyAppend...); err != nil {
+		return err
+	}
+	return t.EvictLocal(key, keyAppend...)
+}
+
+// EvictLocal 仅清除本地缓存，用于处理其他节点发出的失效通知，本地缓存中不存在的 key 视为清除成功
+func (t *TieredBucket) EvictLocal(key CacheKey, keyAppend ...interface{}) error {
+	if err := t.local.Evict(key, keyAppend...); err != nil && !isEntryNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// localTTL 获取本地缓存的过期时间，取远程剩余过期时间 remoteTTL 与 LocalTTL 中较短的一个，0 表示不过期
+func (t *TieredBucket) localTTL(remoteTTL time.Duration) time.Duration {
+	localTTL := t.option.LocalTTL
+	if remoteTTL > 0 && (localTTL <= 0 || remoteTTL < localTTL) {
+		localTTL = remoteTTL
+	}
+	return localTTL
+}
+
+func (t *TieredBucket) afterRemotePut(key CacheKey, data []byte, localTTL time.Duration, keyAppend ...interface{}) error {
+	if err := t.putLocal(key, data, localTTL, keyAppend...); err != nil {
+		// 本地缓存写入失败时清除旧值，避免读取到过期数据
+		_ = t.EvictLocal(key, keyAppend...)
+		if !errors.Is(err, toolkitError.ErrTTLNotSupported) {
+			logger.Logrus().Warningln("caching: tiered local put failed", err)
+		}
+	}
+	t.invalidate(key, keyAppend...)
+	return nil
+}
+
+// putLocal 写入本地缓存，ttl 只可能来自 LocalTTL 或远程缓存的过期时间
+func (t *TieredBucket) putLocal(key CacheKey, data []byte, ttl time.Duration, keyAppend ...interface{}) error {
+	if ttl <= 0 {
+		return t.local.PutBytes(key, data, keyAppend...)
+	}
+	local, ok := t.local.(TTLCacheBucket)
+	if !ok {
+		// 本地缓存无法设置过期时间时不写入，避免本地副本比远程缓存存活更久
+		_ = t.EvictLocal(key, keyAppend...)
+		return toolkitError.ErrTTLNotSupported
+	}
+	return local.PutBytesWithTTL(key, data, ttl, keyAppend...)
+}
+
+func (t *TieredBucket) invalidate(key CacheKey, keyAppend ...interface{}) {
+	if t.option.OnInvalidate != nil {
+		t.option.OnInvalidate(key.RawKeyString(keyAppend...))
+	}
+}
@@ -0,0 +1,225 @@
+package caching
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestTieredBucket(t *testing.T, local, remote CacheBucket, option ...TieredBucketOption) *TieredBucket {
+	t.Helper()
+
+	bucket, err := NewTieredBucket(local, remote, option...)
+	if err != nil {
+		t.Fatalf("new tiered bucket: %v", err)
+	}
+	return bucket
+}
+
+// newTestTieredNodes 创建共享同一个远程缓存桶的两个节点，节点间通过 OnInvalidate 互相通知
+func newTestTieredNodes(t *testing.T) (*TieredBucket, *TieredBucket, *MemoryBucket) {
+	t.Helper()
+
+	remote := newTestMemoryBucket(t, MemoryBucketOption{})
+	var nodeA, nodeB *TieredBucket
+	nodeA = newTestTieredBucket(t, newTestMemoryBucket(t, MemoryBucketOption{}), remote, TieredBucketOption{
+		OnInvalidate: func(rawKey string) {
+			_ = nodeB.EvictLocal(NewCacheKey(rawKey))
+		},
+	})
+	nodeB = newTestTieredBucket(t, newTestMemoryBucket(t, MemoryBucketOption{}), remote, TieredBucketOption{
+		OnInvalidate: func(rawKey string) {
+			_ = nodeA.EvictLocal(NewCacheKey(rawKey))
+		},
+	})
+	return nodeA, nodeB, remote
+}
+
+func TestTieredBucketFillsLocalOnRemoteHit(t *testing.T) {
+	local := newTestMemoryBucket(t, MemoryBucketOption{})
+	remote := newTestMemoryBucket(t, MemoryBucketOption{})
+	bucket := newTestTieredBucket(t, local, remote)
+	key := NewCacheKey("tiered:%d")
+
+	if err := remote.PutBytes(key, []byte("remote"), 1); err != nil {
+		t.Fatalf("put remote: %v", err)
+	}
+	got, err := bucket.GetBytes(key, 1)
+	if err != nil || string(got) != "remote" {
+		t.Fatalf("unexpected tiered value %q, %v", got, err)
+	}
+	if got, err = local.GetBytes(key, 1); err != nil || string(got) != "remote" {
+		t.Fatalf("expected local fill, got %q, %v", got, err)
+	}
+}
+
+func TestTieredBucketWritesBothLevels(t *testing.T) {
+	local := newTestMemoryBucket(t, MemoryBucketOption{})
+	remote := newTestMemoryBucket(t, MemoryBucketOption{})
+	bucket := newTestTieredBucket(t, local, remote)
+	key := NewCacheKey("tiered:write")
+
+	if err := bucket.PutBytes(key, []byte("value")); err != nil {
+		t.Fatalf("put tiered: %v", err)
+	}
+	for name, level := range map[string]CacheBucket{"local": local, "remote": remote} {
+		if got, err := level.GetBytes(key); err != nil || string(got) != "value" {
+			t.Fatalf("expected %s value, got %q, %v", name, got, err)
+		}
+	}
+
+	if err := bucket.Evict(key); err != nil {
+		t.Fatalf("evict tiered: %v", err)
+	}
+	for name, level := range map[string]CacheBucket{"local": local, "remote": remote} {
+		if _, err := level.GetBytes(key); !errors.Is(err, toolkitError.ErrCacheMiss) {
+			t.Fatalf("expected %s miss after evict, got %v", name, err)
+		}
+	}
+}
+
+func TestTieredBucketInvalidatesOtherNodes(t *testing.T) {
+	nodeA, nodeB, _ := newTestTieredNodes(t)
+	key := NewCacheKey("tiered:shared:%d")
+
+	if err := nodeA.PutBytes(key, []byte("v1"), 1); err != nil {
+		t.Fatalf("put node a: %v", err)
+	}
+	if got, err := nodeB.GetBytes(key, 1); err != nil || string(got) != "v1" {
+		t.Fatalf("unexpected node b value %q, %v", got, err)
+	}
+
+	if err := nodeA.PutBytes(key, []byte("v2"), 1); err != nil {
+		t.Fatalf("update node a: %v", err)
+	}
+	if got, err := nodeB.GetBytes(key, 1); err != nil || string(got) != "v2" {
+		t.Fatalf("expected node b to see update, got %q, %v", got, err)
+	}
+
+}
+
+// countingTransport 记录发布次数的失效通知传输
+type countingTransport struct {
+	published int
+}
+
+func (c *countingTransport) Publish(payload []byte) error {
+	c.published++
+	return nil
+}
+
+func (c *countingTransport) Subscribe(handler func(payload []byte)) error {
+	return nil
+}
+
+func (c *countingTransport) Close() error {
+	return nil
+}
+
+func TestTieredBucketPublishesEvictionOnce(t *testing.T) {
+	transport := &countingTransport{}
+	bucketName := NewBucketName("tiered-once")
+	manager := NewCacheManager()
+	bucket := newTestTieredBucket(t, newTestMemoryBucket(t, MemoryBucketOption{}), newTestMemoryBucket(t, MemoryBucketOption{}), TieredBucketOption{
+		OnInvalidate: func(rawKey string) {
+			_ = manager.PublishInvalidation(bucketName, rawKey)
+		},
+	})
+	manager.AddBucket(bucketName, bucket)
+	if err := manager.EnableInvalidation(transport); err != nil {
+		t.Fatalf("enable invalidation: %v", err)
+	}
+	key := NewCacheKey("tiered:once")
+
+	if err := manager.PutBytes(bucketName, key, []byte("value")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if transport.published != 1 {
+		t.Fatalf("expected write to publish once, got %d", transport.published)
+	}
+	if err := manager.Evict(bucketName, key); err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+	if transport.published != 2 {
+		t.Fatalf("expected evict to publish once, got %d", transport.published-1)
+	}
+}
+
+func TestTieredBucketTTL(t *testing.T) {
+	local := newTestMemoryBucket(t, MemoryBucketOption{})
+	remote := newTestMemoryBucket(t, MemoryBucketOption{})
+	bucket := newTestTieredBucket(t, local, remote, TieredBucketOption{LocalTTL: 20 * time.Millisecond})
+	key := NewCacheKey("tiered:ttl")
+
+	if err := bucket.PutBytesWithTTL(key, []byte("value"), time.Minute); err != nil {
+		t.Fatalf("put tiered with ttl: %v", err)
+	}
+	if ttl, err := bucket.TTL(key); err != nil || ttl <= 20*time.Millisecond {
+		t.Fatalf("expected remote ttl, got %v, %v", ttl, err)
+	}
+	if ttl, err := local.TTL(key); err != nil || ttl > 20*time.Millisecond {
+		t.Fatalf("expected local ttl bounded by LocalTTL, got %v, %v", ttl, err)
+	}
+
+	plain := newTestTieredBucket(t, local, plainBucket{CacheBucket: remote})
+	if err := plain.PutBytesWithTTL(key, []byte("value"), time.Minute); !errors.Is(err, toolkitError.ErrTTLNotSupported) {
+		t.Fatalf("expected ErrTTLNotSupported, got %v", err)
+	}
+
+	// 本地缓存桶无法设置过期时间时不能满足 LocalTTL
+	_, err := NewTieredBucket(plainBucket{CacheBucket: local}, remote, TieredBucketOption{LocalTTL: time.Second})
+	if !errors.Is(err, toolkitError.ErrTTLNotSupported) {
+		t.Fatalf("expected ErrTTLNotSupported for local bucket without ttl, got %v", err)
+	}
+
+	// 远程写入带过期时间而本地缓存桶无法设置过期时间时，本地不保留副本
+	plainLocal := newTestMemoryBucket(t, MemoryBucketOption{})
+	noTTLLocal := newTestTieredBucket(t, plainBucket{CacheBucket: plainLocal}, remote)
+	if err = noTTLLocal.PutBytesWithTTL(key, []byte("value"), time.Minute); err != nil {
+		t.Fatalf("put tiered with ttl: %v", err)
+	}
+	if _, err = plainLocal.GetBytes(key); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected no local copy without ttl support, got %v", err)
+	}
+}
+
+func TestTieredBucketFillKeepsRemoteExpiry(t *testing.T) {
+	for _, localTTL := range []time.Duration{0, time.Minute} {
+		remote := newTestMemoryBucket(t, MemoryBucketOption{})
+		local := newTestMemoryBucket(t, MemoryBucketOption{})
+		bucket := newTestTieredBucket(t, local, remote, TieredBucketOption{LocalTTL: localTTL})
+		key := NewCacheKey("tiered:fill")
+		if err := remote.PutBytesWithTTL(key, []byte("value"), 30*time.Millisecond); err != nil {
+			t.Fatalf("put remote: %v", err)
+		}
+
+		if bs, err := bucket.GetBytes(key); err != nil || string(bs) != "value" {
+			t.Fatalf("unexpected remote hit %q, %v", bs, err)
+		}
+		if ttl, err := local.TTL(key); err != nil || ttl <= 0 || ttl > 30*time.Millisecond {
+			t.Fatalf("expected local ttl bounded by remote with LocalTTL %v, got %v, %v", localTTL, ttl, err)
+		}
+		time.Sleep(40 * time.Millisecond)
+		// 远程过期后本地回填的副本同样过期
+		if _, err := bucket.GetBytes(key); !errors.Is(err, toolkitError.ErrCacheMiss) {
+			t.Fatalf("expected miss after remote expiry with LocalTTL %v, got %v", localTTL, err)
+		}
+	}
+}
+
+func TestTieredBucketWithBigCacheLocal(t *testing.T) {
+	local := newTestBigCache(t)
+	bucket := newTestTieredBucket(t, local, newTestMemoryBucket(t, MemoryBucketOption{}))
+	if err := bucket.Evict(NewCacheKey("tiered:absent")); err != nil {
+		t.Fatalf("expected evicting absent key to succeed, got %v", err)
+	}
+	if err := bucket.EvictLocal(NewCacheKey("tiered:absent")); err != nil {
+		t.Fatalf("expected evicting absent local key to succeed, got %v", err)
+	}
+	// BigCacheBucket 本身仍然报告不存在的 key
+	if err := local.Evict(NewCacheKey("tiered:absent")); !isEntryNotFound(err) {
+		t.Fatalf("expected bigcache ErrEntryNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package caching
+
+import (
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// TypedCache 绑定单个缓存桶、单个 key 格式和单一值类型的泛型缓存
+// 所有读写仍然通过 CacheManager 的 Codec 进行编解码，与直接使用 CacheManager 写入的数据互相兼容
+type TypedCache[K any, V any] struct {
+	manager    *CacheManager
+	bucketName BucketName
+	key        CacheKey
+}
+
+// NewTypedCache 创建泛型缓存，key 的 KeyFormat 将使用 K 类型的值进行格式化
+func NewTypedCache[K any, V any](manager *CacheManager, bucketName BucketName, key CacheKey) *TypedCache[K, V] {
+	return &TypedCache[K, V]{
+		manager:    manager,
+		bucketName: bucketName,
+		key:        key,
+	}
+}
+
+// BucketName 获取绑定的缓存桶名称
+func (t *TypedCache[K, V]) BucketName() BucketName {
+	return t.bucketName
+}
+
+// RawKeyString 获取指定 key 对应的原始 key 字符串
+func (t *TypedCache[K, V]) RawKeyString(key K) string {
+	return t.key.RawKeyString(key)
+}
+
+// Get 获取缓存值，未命中时返回 V 的零值和 ErrCacheMiss
+func (t *TypedCache[K, V]) Get(key K) (V, error) {
+	var result V
+	if err := t.manager.Get(t.bucketName, t.key, &result, key); err != nil {
+		var zero V
+		return zero, err
+	}
+	return result, nil
+}
+
+// Put 缓存数据
+func (t *TypedCache[K, V]) Put(key K, value V) error {
+	return t.manager.Put(t.bucketName, t.key, value, key)
+}
+
+// Evict 清除缓存
+func (t *TypedCache[K, V]) Evict(key K) error {
+	return t.manager.Evict(t.bucketName, t.key, key)
+}
+
+// GetOrLoad 获取缓存，未命中时调用 loader 加载并写入缓存，并发未命中只会调用一次 loader
+func (t *TypedCache[K, V]) GetOrLoad(key K, loader func() (V, error)) (V, error) {
+	return t.GetOrLoadWithOption(key, loader, LoadOption{})
+}
+
+// GetOrLoadWithOption 按指定配置获取缓存，未命中时调用 loader 加载并写入缓存
+func (t *TypedCache[K, V]) GetOrLoadWithOption(key K, loader func() (V, error), option LoadOption) (V, error) {
+	var result V
+	err := t.manager.GetOrLoadWithOption(t.bucketName, t.key, &result, func() (any, error) {
+		return loader()
+	}, option, key)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return result, nil
+}
+
+// GetMany 批量获取缓存，values 与 keys 按位置一一对应，未命中的位置为 V 的零值，misses 为所有未命中的 key
+func (t *TypedCache[K, V]) GetMany(keys []K) (values []V, misses []K, err error) {
+	result, err := t.manager.GetMany(t.bucketName, t.key, typedKeyAppends(keys))
+	if err != nil {
+		return nil, nil, err
+	}
+	values = make([]V, len(keys))
+	for i, key := range keys {
+		if !result.Hit(i) {
+			misses = append(misses, key)
+			continue
+		}
+		if err = result.Decode(i, &values[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return values, misses, nil
+}
+
+// PutMany 批量缓存数据，values 与 keys 按位置一一对应
+func (t *TypedCache[K, V]) PutMany(keys []K, values []V) error {
+	if len(keys) != len(values) {
+		return toolkitError.ErrBatchSizeMismatch
+	}
+	data := make([]any, len(values))
+	for i, value := range values {
+		data[i] = value
+	}
+	return t.manager.PutMany(t.bucketName, t.key, typedKeyAppends(keys), data)
+}
+
+// EvictMany 批量清除缓存
+func (t *TypedCache[K, V]) EvictMany(keys []K) error {
+	return t.manager.EvictMany(t.bucketName, t.key, typedKeyAppends(keys))
+}
+
+func typedKeyAppends[K any](keys []K) [][]interface{} {
+	keyAppends := make([][]interface{}, len(keys))
+	for i, key := range keys {
+		keyAppends[i] = []interface{}{key}
+	}
+	return keyAppends
+}
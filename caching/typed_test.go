@@ -0,0 +1,70 @@
+package caching
+
+import (
+	"errors"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestTypedCachePutGet(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	users := NewTypedCache[int, testUser](manager, bucketName, NewCacheKey("typed:user:%d"))
+	want := testUser{Name: "Typed", Sex: 1}
+
+	if err := users.Put(1, want); err != nil {
+		t.Fatalf("put typed cache: %v", err)
+	}
+	got, err := users.Get(1)
+	if err != nil {
+		t.Fatalf("get typed cache: %v", err)
+	}
+	if got != want {
+		t.Fatalf("unexpected typed value: got %+v, want %+v", got, want)
+	}
+}
+
+func TestTypedCacheMissReturnsZeroValue(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	users := NewTypedCache[string, testUser](manager, bucketName, NewCacheKey("typed:miss:%s"))
+
+	got, err := users.Get("missing")
+	if !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss, got %v", err)
+	}
+	if got != (testUser{}) {
+		t.Fatalf("expected zero value on miss, got %+v", got)
+	}
+}
+
+func TestTypedCacheSharesBytesWithManager(t *testing.T) {
+	manager, bucketName := newTestCacheManager(t)
+	key := NewCacheKey("typed:shared:%d")
+	users := NewTypedCache[int, testUser](manager, bucketName, key)
+	want := testUser{Name: "Shared", Sex: 2}
+
+	if err := manager.Put(bucketName, key, want, 7); err != nil {
+		t.Fatalf("put cache: %v", err)
+	}
+	got, err := users.Get(7)
+	if err != nil {
+		t.Fatalf("get typed cache: %v", err)
+	}
+	if got != want {
+		t.Fatalf("unexpected typed value: got %+v, want %+v", got, want)
+	}
+
+	if err = users.Evict(7); err != nil {
+		t.Fatalf("evict typed cache: %v", err)
+	}
+	if _, err = manager.GetBytes(bucketName, key, 7); !errors.Is(err, toolkitError.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after typed evict, got %v", err)
+	}
+}
+
+func TestTypedCacheBadBucketName(t *testing.T) {
+	users := NewTypedCache[int, testUser](NewCacheManager(), NewBucketName("missing"), NewCacheKey("typed:%d"))
+	if _, err := users.Get(1); !errors.Is(err, toolkitError.ErrBadBucketName) {
+		t.Fatalf("expected ErrBadBucketName, got %v", err)
+	}
+}
@@ -54,6 +54,26 @@ type EcdsaKeyPairManager interface {
 	KeyPairManager[EcdsaKeyPair]
 }
 
+// Ed25519KeyPair Ed25519 专用公私钥信息
+type Ed25519KeyPair interface {
+	KeyPair
+}
+
+// Ed25519KeyPairManager Ed25519 专用 KeyPair 管理器
+type Ed25519KeyPairManager interface {
+	KeyPairManager[Ed25519KeyPair]
+}
+
+// X25519KeyPair X25519 专用公私钥信息，仅用于密钥协商
+type X25519KeyPair interface {
+	KeyPair
+}
+
+// X25519KeyPairManager X25519 专用 KeyPair 管理器
+type X25519KeyPairManager interface {
+	KeyPairManager[X25519KeyPair]
+}
+
 type CryptEncrypt interface {
 	// Encrypt 加密
 	Encrypt(keyPair KeyPair, raw []byte) ([]byte, error)
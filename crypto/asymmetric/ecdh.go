@@ -0,0 +1,62 @@
+package asymmetric
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+
+	"github.com/acexy/golang-toolkit/crypto/symmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// derivedAESKeySize 由共享密钥派生的 AES 密钥长度，对应 AES-256
+const derivedAESKeySize = 32
+
+// SharedSecret 使用本方 ECDSA 私钥和对方 ECDSA 公钥计算 ECDH 共享密钥，双方必须使用相同曲线
+// 共享密钥不应直接作为对称密钥使用，需要经过 KDF 派生，参见 DeriveAESKey
+// 同一密钥同时用于签名和密钥协商时，建议为两种用途分别生成密钥
+func (e *EcdsaKeyManager) SharedSecret(keyPair, peer KeyPair) ([]byte, error) {
+	ecdsaPrivateKey, err := loadEcdsaPrivateKey(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaPublicKey, err := loadEcdsaPublicKey(peer)
+	if err != nil {
+		return nil, err
+	}
+	if ecdsaPrivateKey.Curve != ecdsaPublicKey.Curve {
+		return nil, toolkitError.ErrCurveMismatch
+	}
+	privateKey, err := ecdsaPrivateKey.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := ecdsaPublicKey.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	return privateKey.ECDH(publicKey)
+}
+
+// DeriveAESKey 计算 ECDH 共享密钥并使用 HKDF-SHA256 派生可直接用于 symmetric.NewAES 的 AES-256 密钥
+// 双方需使用相同的 salt 和 info，info 建议包含业务用途以区分不同场景派生的密钥
+func (e *EcdsaKeyManager) DeriveAESKey(keyPair, peer KeyPair, salt, info []byte) ([]byte, error) {
+	secret, err := e.SharedSecret(keyPair, peer)
+	if err != nil {
+		return nil, err
+	}
+	return deriveKey(secret, salt, info, derivedAESKeySize)
+}
+
+// DeriveAES 计算 ECDH 共享密钥并派生 AES-256 密钥，使用 option 创建 AES 加解密实例
+func (e *EcdsaKeyManager) DeriveAES(keyPair, peer KeyPair, salt, info []byte, option symmetric.AESOption) (*symmetric.AESEncrypt, error) {
+	key, err := e.DeriveAESKey(keyPair, peer, salt, info)
+	if err != nil {
+		return nil, err
+	}
+	return symmetric.NewAESWithOption(key, option)
+}
+
+// deriveKey 使用 HKDF-SHA256 从共享密钥派生指定长度的密钥
+func deriveKey(secret, salt, info []byte, length int) ([]byte, error) {
+	return hkdf.Key(sha256.New, secret, salt, string(info), length)
+}
@@ -0,0 +1,92 @@
+package asymmetric
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"testing"
+
+	"github.com/acexy/golang-toolkit/crypto/symmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestEcdsaSharedSecret(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		manager := NewEcdsaKeyManager(curve)
+		alice, err := manager.Create()
+		if err != nil {
+			t.Fatalf("create alice key pair: %v", err)
+		}
+		bob, err := manager.Create()
+		if err != nil {
+			t.Fatalf("create bob key pair: %v", err)
+		}
+		bobPublic, err := manager.LoadPublicKey(mustPublicPem(t, bob))
+		if err != nil {
+			t.Fatalf("load bob public key: %v", err)
+		}
+		alicePublic, err := manager.LoadPublicKey(mustPublicPem(t, alice))
+		if err != nil {
+			t.Fatalf("load alice public key: %v", err)
+		}
+
+		aliceSecret, err := manager.SharedSecret(alice, bobPublic)
+		if err != nil {
+			t.Fatalf("alice shared secret: %v", err)
+		}
+		bobSecret, err := manager.SharedSecret(bob, alicePublic)
+		if err != nil || !bytes.Equal(aliceSecret, bobSecret) {
+			t.Fatalf("%s: expected equal shared secrets, %v", curve.Params().Name, err)
+		}
+
+		salt, info := []byte("session-1"), []byte("toolkit ecdh test")
+		aliceKey, err := manager.DeriveAESKey(alice, bobPublic, salt, info)
+		if err != nil {
+			t.Fatalf("alice derive key: %v", err)
+		}
+		if _, err = symmetric.NewAES(aliceKey); err != nil {
+			t.Fatalf("expected derived key to be a valid aes key: %v", err)
+		}
+		otherSalt, _ := manager.DeriveAESKey(alice, bobPublic, []byte("session-2"), info)
+		if bytes.Equal(aliceKey, otherSalt) {
+			t.Fatal("expected salt to separate derived keys")
+		}
+
+		option := symmetric.AESOption{Mode: symmetric.AESModeGCM}
+		aliceAES, err := manager.DeriveAES(alice, bobPublic, salt, info, option)
+		if err != nil {
+			t.Fatalf("alice derive aes: %v", err)
+		}
+		bobAES, err := manager.DeriveAES(bob, alicePublic, salt, info, option)
+		if err != nil {
+			t.Fatalf("bob derive aes: %v", err)
+		}
+		cipherData, err := aliceAES.Encrypt([]byte("hello bob"))
+		if err != nil {
+			t.Fatalf("encrypt: %v", err)
+		}
+		plain, err := bobAES.Decrypt(cipherData)
+		if err != nil || string(plain) != "hello bob" {
+			t.Fatalf("unexpected decrypted %q, %v", plain, err)
+		}
+	}
+}
+
+func TestEcdsaSharedSecretRejectsBadKeys(t *testing.T) {
+	manager := NewEcdsaKeyManager(elliptic.P256())
+	alice := newTestEcdsaKeyPair(t)
+	p384, err := NewEcdsaKeyManager(elliptic.P384()).Create()
+	if err != nil {
+		t.Fatalf("create p384 key pair: %v", err)
+	}
+	if _, err = manager.SharedSecret(alice, p384); !errors.Is(err, toolkitError.ErrCurveMismatch) {
+		t.Fatalf("expected ErrCurveMismatch, got %v", err)
+	}
+	publicOnly, _ := manager.LoadPublicKey(mustPublicPem(t, alice))
+	if _, err = manager.SharedSecret(publicOnly, alice); !errors.Is(err, toolkitError.ErrNilPrivateKey) {
+		t.Fatalf("expected ErrNilPrivateKey, got %v", err)
+	}
+	if _, err = manager.SharedSecret(alice, newTestX25519KeyPair(t)); !errors.Is(err, toolkitError.ErrNotEcdsaPublicKey) {
+		t.Fatalf("expected ErrNotEcdsaPublicKey, got %v", err)
+	}
+}
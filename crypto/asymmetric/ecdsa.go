@@ -58,6 +58,22 @@ func (e *EcdsaKeyManager) LoadPrivateKey(priPem string) (EcdsaKeyPair, error) {
 	return e.LoadKeyPair("", priPem)
 }
 
+// LoadEncryptedPrivateKey 使用口令从加密的 PKCS8 或旧版 Proc-Type 加密 PEM 加载 ECDSA 私钥，未加密的 PEM 同样可以加载
+func (e *EcdsaKeyManager) LoadEncryptedPrivateKey(priPem string, passphrase []byte) (EcdsaKeyPair, error) {
+	return e.LoadEncryptedKeyPair("", priPem, passphrase)
+}
+
+// LoadEncryptedKeyPair 使用口令解密私钥后加载 ECDSA 公私钥，并校验二者是否匹配
+func (e *EcdsaKeyManager) LoadEncryptedKeyPair(pubPem, priPem string, passphrase []byte) (EcdsaKeyPair, error) {
+	if priPem != "" {
+		var err error
+		if priPem, err = decryptPrivatePem(priPem, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return e.LoadKeyPair(pubPem, priPem)
+}
+
 // LoadKeyPair 从 PEM 字符串加载 ECDSA 公私钥，并校验二者是否匹配
 func (e *EcdsaKeyManager) LoadKeyPair(pubPem, priPem string) (EcdsaKeyPair, error) {
 	if pubPem == "" && priPem == "" {
@@ -0,0 +1,266 @@
+package asymmetric
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/math/conversion"
+)
+
+// NewEd25519KeyManager 创建 Ed25519 KeyManager
+func NewEd25519KeyManager() *Ed25519KeyManager {
+	return &Ed25519KeyManager{}
+}
+
+// Ed25519KeyManager 管理 Ed25519 密钥创建和 PEM 加载
+type Ed25519KeyManager struct{}
+
+// Create 创建新的 Ed25519 公私钥对
+func (e *Ed25519KeyManager) Create() (Ed25519KeyPair, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &ed25519Key{
+		publicKey:  publicKey,
+		privateKey: privateKey,
+	}, nil
+}
+
+// LoadPublicKey 从 PKIX PEM 字符串加载 Ed25519 公钥
+func (e *Ed25519KeyManager) LoadPublicKey(pubPem string) (Ed25519KeyPair, error) {
+	return e.LoadKeyPair(pubPem, "")
+}
+
+// LoadPrivateKey 从 PKCS8 PEM 字符串加载 Ed25519 私钥，并派生对应公钥
+func (e *Ed25519KeyManager) LoadPrivateKey(priPem string) (Ed25519KeyPair, error) {
+	return e.LoadKeyPair("", priPem)
+}
+
+// LoadKeyPair 从 PEM 字符串加载 Ed25519 公私钥，并校验二者是否匹配
+func (e *Ed25519KeyManager) LoadKeyPair(pubPem, priPem string) (Ed25519KeyPair, error) {
+	if pubPem == "" && priPem == "" {
+		return nil, toolkitError.ErrBadKey
+	}
+
+	var pub ed25519.PublicKey
+	var pri ed25519.PrivateKey
+
+	if pubPem != "" {
+		iface, err := parsePKIXPublicPem(pubPem)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		pub, ok = iface.(ed25519.PublicKey)
+		if !ok {
+			return nil, toolkitError.ErrNotEd25519PublicKey
+		}
+	}
+	if priPem != "" {
+		iface, err := parsePKCS8PrivatePem(priPem)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		pri, ok = iface.(ed25519.PrivateKey)
+		if !ok {
+			return nil, toolkitError.ErrNotEd25519PrivateKey
+		}
+	}
+	if pub == nil && pri != nil {
+		pub = pri.Public().(ed25519.PublicKey)
+	}
+	if pub != nil && pri != nil && !pub.Equal(pri.Public()) {
+		return nil, toolkitError.ErrKeyPairMismatch
+	}
+
+	return &ed25519Key{
+		publicKey:  pub,
+		privateKey: pri,
+	}, nil
+}
+
+// parsePKIXPublicPem 解析 PUBLIC KEY 类型的 PEM 公钥
+func parsePKIXPublicPem(pubPem string) (any, error) {
+	block, _ := pem.Decode(conversion.ParseBytes(pubPem))
+	if block == nil {
+		return nil, toolkitError.ErrBadPublicKey
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, toolkitError.ErrUnsupportedPublicKeyType
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// parsePKCS8PrivatePem 解析 PRIVATE KEY 类型的 PEM 私钥
+func parsePKCS8PrivatePem(priPem string) (any, error) {
+	block, _ := pem.Decode(conversion.ParseBytes(priPem))
+	if block == nil {
+		return nil, toolkitError.ErrBadPrivateKey
+	}
+	if block.Type != "PRIVATE KEY" {
+		return nil, toolkitError.ErrUnsupportedPrivateKeyType
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+// marshalPKIXPublicPem 将公钥导出为 PKIX PEM 格式
+func marshalPKIXPublicPem(publicKey any) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	})), nil
+}
+
+// marshalPKCS8PrivatePem 将私钥导出为 PKCS8 PEM 格式
+func marshalPKCS8PrivatePem(privateKey any) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	})), nil
+}
+
+type ed25519Key struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// PrivateKey 返回原始 Ed25519 私钥
+func (e *ed25519Key) PrivateKey() any {
+	if e.privateKey == nil {
+		return nil
+	}
+	return e.privateKey
+}
+
+// PublicKey 返回原始 Ed25519 公钥
+func (e *ed25519Key) PublicKey() any {
+	if e.publicKey == nil {
+		return nil
+	}
+	return e.publicKey
+}
+
+// ToPublicPem 将 Ed25519 公钥导出为默认 PKIX PEM 格式
+func (e *ed25519Key) ToPublicPem() (string, error) {
+	return e.ToPKIXPublicPem()
+}
+
+// ToPrivatePem 将 Ed25519 私钥导出为默认 PKCS8 PEM 格式
+func (e *ed25519Key) ToPrivatePem() (string, error) {
+	return e.ToPKCS8PrivatePem()
+}
+
+// ToPKIXPublicPem 将 Ed25519 公钥导出为 PKIX PEM 格式
+func (e *ed25519Key) ToPKIXPublicPem() (string, error) {
+	if e.publicKey == nil {
+		return "", toolkitError.ErrNilPublicKey
+	}
+	return marshalPKIXPublicPem(e.publicKey)
+}
+
+// ToPKCS8PrivatePem 将 Ed25519 私钥导出为 PKCS8 PEM 格式
+func (e *ed25519Key) ToPKCS8PrivatePem() (string, error) {
+	if e.privateKey == nil {
+		return "", toolkitError.ErrNilPrivateKey
+	}
+	return marshalPKCS8PrivatePem(e.privateKey)
+}
+
+// Ed25519Sign 提供 Ed25519 签名和验签能力，Ed25519 在算法内部完成摘要，raw 直接传入原文
+type Ed25519Sign struct{}
+
+// NewEd25519Sign 创建 Ed25519 签名验签实例
+func NewEd25519Sign() *Ed25519Sign {
+	return &Ed25519Sign{}
+}
+
+func loadEd25519PublicKey(keyPair KeyPair) (ed25519.PublicKey, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	publicKey := keyPair.PublicKey()
+	if publicKey == nil {
+		return nil, toolkitError.ErrNilPublicKey
+	}
+	ed25519PublicKey, ok := publicKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, toolkitError.ErrNotEd25519PublicKey
+	}
+	return ed25519PublicKey, nil
+}
+
+func loadEd25519PrivateKey(keyPair KeyPair) (ed25519.PrivateKey, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	privateKey := keyPair.PrivateKey()
+	if privateKey == nil {
+		return nil, toolkitError.ErrNilPrivateKey
+	}
+	ed25519PrivateKey, ok := privateKey.(ed25519.PrivateKey)
+	if !ok {
+		return nil, toolkitError.ErrNotEd25519PrivateKey
+	}
+	return ed25519PrivateKey, nil
+}
+
+// Sign 使用 Ed25519 私钥对原文签名
+func (e *Ed25519Sign) Sign(keyPair KeyPair, raw []byte) ([]byte, error) {
+	privateKey, err := loadEd25519PrivateKey(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(privateKey, raw), nil
+}
+
+// Verify 使用 Ed25519 公钥验证签名
+func (e *Ed25519Sign) Verify(keyPair KeyPair, raw, sign []byte) error {
+	publicKey, err := loadEd25519PublicKey(keyPair)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, raw, sign) {
+		return toolkitError.ErrVerifyFailed
+	}
+	return nil
+}
+
+// SignBase64 解码 Base64 原文后签名，并返回 Base64 签名
+func (e *Ed25519Sign) SignBase64(keyPair KeyPair, base64Raw string) (string, error) {
+	content, err := base64.StdEncoding.DecodeString(base64Raw)
+	if err != nil {
+		return "", err
+	}
+	result, err := e.Sign(keyPair, content)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(result), nil
+}
+
+// VerifyBase64 解码 Base64 原文和签名后执行验签
+func (e *Ed25519Sign) VerifyBase64(keyPair KeyPair, base64Raw, base64Sign string) error {
+	rawContent, err := base64.StdEncoding.DecodeString(base64Raw)
+	if err != nil {
+		return err
+	}
+	signContent, err := base64.StdEncoding.DecodeString(base64Sign)
+	if err != nil {
+		return err
+	}
+	return e.Verify(keyPair, rawContent, signContent)
+}
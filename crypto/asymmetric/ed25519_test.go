@@ -0,0 +1,88 @@
+package asymmetric
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestEd25519KeyPair(t *testing.T) Ed25519KeyPair {
+	t.Helper()
+
+	keyPair, err := NewEd25519KeyManager().Create()
+	if err != nil {
+		t.Fatalf("create ed25519 key pair: %v", err)
+	}
+	return keyPair
+}
+
+func TestEd25519PemRoundTrip(t *testing.T) {
+	keyPair := newTestEd25519KeyPair(t)
+	publicPem, err := keyPair.ToPublicPem()
+	if err != nil {
+		t.Fatalf("export public pem: %v", err)
+	}
+	privatePem, err := keyPair.ToPrivatePem()
+	if err != nil {
+		t.Fatalf("export private pem: %v", err)
+	}
+
+	manager := NewEd25519KeyManager()
+	loaded, err := manager.LoadKeyPair(publicPem, privatePem)
+	if err != nil {
+		t.Fatalf("load ed25519 key pair: %v", err)
+	}
+	if !samePublicKey(keyPair.PublicKey(), loaded.PublicKey()) {
+		t.Fatal("expected loaded public key to match")
+	}
+	derived, err := manager.LoadPrivateKey(privatePem)
+	if err != nil || !samePublicKey(keyPair.PublicKey(), derived.PublicKey()) {
+		t.Fatalf("expected public key derived from private key, %v", err)
+	}
+
+	otherPublicPem := mustPublicPem(t, newTestEd25519KeyPair(t))
+	if _, err = manager.LoadKeyPair(otherPublicPem, privatePem); !errors.Is(err, toolkitError.ErrKeyPairMismatch) {
+		t.Fatalf("expected ErrKeyPairMismatch, got %v", err)
+	}
+	if _, err = manager.LoadPublicKey(mustPublicPem(t, newTestEcdsaKeyPair(t))); !errors.Is(err, toolkitError.ErrNotEd25519PublicKey) {
+		t.Fatalf("expected ErrNotEd25519PublicKey, got %v", err)
+	}
+}
+
+func TestEd25519SignVerify(t *testing.T) {
+	keyPair := newTestEd25519KeyPair(t)
+	var signer CryptSign = NewEd25519Sign()
+	raw := []byte("ed25519 message")
+
+	sign, err := signer.Sign(keyPair, raw)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	publicOnly, err := NewEd25519KeyManager().LoadPublicKey(mustPublicPem(t, keyPair))
+	if err != nil {
+		t.Fatalf("load public key: %v", err)
+	}
+	if err = signer.Verify(publicOnly, raw, sign); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err = signer.Verify(publicOnly, []byte("tampered"), sign); !errors.Is(err, toolkitError.ErrVerifyFailed) {
+		t.Fatalf("expected ErrVerifyFailed, got %v", err)
+	}
+	if _, err = signer.Sign(publicOnly, raw); !errors.Is(err, toolkitError.ErrNilPrivateKey) {
+		t.Fatalf("expected ErrNilPrivateKey, got %v", err)
+	}
+	if _, err = signer.Sign(newTestEcdsaKeyPair(t), raw); !errors.Is(err, toolkitError.ErrNotEd25519PrivateKey) {
+		t.Fatalf("expected ErrNotEd25519PrivateKey, got %v", err)
+	}
+
+	base64Raw := base64.StdEncoding.EncodeToString(raw)
+	base64Sign, err := signer.SignBase64(keyPair, base64Raw)
+	if err != nil {
+		t.Fatalf("sign base64: %v", err)
+	}
+	if err = signer.VerifyBase64(keyPair, base64Raw, base64Sign); err != nil {
+		t.Fatalf("verify base64: %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package asymmetric
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+
+	"github.com/acexy/golang-toolkit/crypto/symmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// 信封格式：
+//
+//	magic(4) | version(1) | algorithm(1) | wrappedKeyLength(uint16 BE) | wrappedKey | payload
+//
+// RSA-OAEP 时 wrappedKey 为 OAEP 加密的数据密钥；
+// ECIES 时 wrappedKey 为临时公钥与 AES-GCM 加密的数据密钥，密钥加密密钥由 ECDH 共享密钥经 HKDF-SHA256 派生；
+// payload 为数据密钥 AES-256-GCM 加密的结果，格式与 symmetric.AESEncrypt 一致
+const (
+	envelopeVersion     byte = 1
+	envelopeHeaderSize       = 8
+	envelopeDataKeySize      = 32
+)
+
+var envelopeMagic = [4]byte{'T', 'K', 'E', 'V'}
+
+// envelopeAlgorithm 信封中数据密钥的包装算法
+type envelopeAlgorithm byte
+
+const (
+	envelopeRsaOAEP envelopeAlgorithm = iota + 1
+	envelopeEciesP256
+	envelopeEciesP384
+	envelopeEciesP521
+	envelopeEciesX25519
+)
+
+// EnvelopeEncrypt 提供混合加密能力，适合加密超过非对称密钥长度限制的大数据
+// 每次加密生成随机 AES-256-GCM 数据密钥，RSA 公钥使用 OAEP(SHA-256) 包装数据密钥，
+// ECDSA 和 X25519 公钥使用相同曲线上的 ECIES 包装数据密钥，接收方只需私钥即可解密
+type EnvelopeEncrypt struct{}
+
+// NewEnvelopeEncrypt 创建信封加解密实例
+func NewEnvelopeEncrypt() *EnvelopeEncrypt {
+	return &EnvelopeEncrypt{}
+}
+
+// Encrypt 使用接收方公钥加密数据，输出自描述的信封格式
+func (e *EnvelopeEncrypt) Encrypt(keyPair KeyPair, raw []byte) ([]byte, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	algorithm, err := envelopeAlgorithmOf(keyPair.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	header := envelopeHeader(algorithm)
+
+	dataKey := make([]byte, envelopeDataKeySize)
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	var wrappedKey []byte
+	if algorithm == envelopeRsaOAEP {
+		wrappedKey, err = wrapKeyWithRsa(keyPair, header, dataKey)
+	} else {
+		wrappedKey, err = wrapKeyWithEcies(keyPair, header, dataKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	crypt, err := symmetric.NewAESWithOption(dataKey, symmetric.AESOption{Mode: symmetric.AESModeGCM})
+	if err != nil {
+		return nil, err
+	}
+	payload, err := crypt.Encrypt(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, envelopeHeaderSize+len(wrappedKey)+len(payload))
+	result = append(result, header...)
+	result = binary.BigEndian.AppendUint16(result, uint16(len(wrappedKey)))
+	result = append(result, wrappedKey...)
+	return append(result, payload...), nil
+}
+
+// Decrypt 使用接收方私钥解密信封
+func (e *EnvelopeEncrypt) Decrypt(keyPair KeyPair, cipher []byte) ([]byte, error) {
+	if len(cipher) < envelopeHeaderSize || [4]byte(cipher[:4]) != envelopeMagic || cipher[4] != envelopeVersion {
+		return nil, toolkitError.ErrInvalidEnvelope
+	}
+	header := cipher[:6]
+	algorithm := envelopeAlgorithm(cipher[5])
+	wrappedKeyLength := int(binary.BigEndian.Uint16(cipher[6:envelopeHeaderSize]))
+	if len(cipher) < envelopeHeaderSize+wrappedKeyLength {
+		return nil, toolkitError.ErrInvalidEnvelope
+	}
+	wrappedKey := cipher[envelopeHeaderSize : envelopeHeaderSize+wrappedKeyLength]
+	payload := cipher[envelopeHeaderSize+wrappedKeyLength:]
+
+	var dataKey []byte
+	var err error
+	switch algorithm {
+	case envelopeRsaOAEP:
+		dataKey, err = unwrapKeyWithRsa(keyPair, header, wrappedKey)
+	case envelopeEciesP256, envelopeEciesP384, envelopeEciesP521, envelopeEciesX25519:
+		dataKey, err = unwrapKeyWithEcies(keyPair, algorithm, header, wrappedKey)
+	default:
+		return nil, toolkitError.ErrInvalidEnvelope
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	crypt, err := symmetric.NewAESWithOption(dataKey, symmetric.AESOption{Mode: symmetric.AESModeGCM})
+	if err != nil {
+		return nil, err
+	}
+	return crypt.Decrypt(payload)
+}
+
+// EncryptBase64 解码 Base64 明文后加密，并返回 Base64 信封
+func (e *EnvelopeEncrypt) EncryptBase64(keyPair KeyPair, base64Raw string) (string, error) {
+	content, err := base64.StdEncoding.DecodeString(base64Raw)
+	if err != nil {
+		return "", err
+	}
+	result, err := e.Encrypt(keyPair, content)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(result), nil
+}
+
+// DecryptBase64 解码 Base64 信封后解密，并返回 Base64 明文
+func (e *EnvelopeEncrypt) DecryptBase64(keyPair KeyPair, base64Cipher string) (string, error) {
+	content, err := base64.StdEncoding.DecodeString(base64Cipher)
+	if err != nil {
+		return "", err
+	}
+	result, err := e.Decrypt(keyPair, content)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(result), nil
+}
+
+func envelopeHeader(algorithm envelopeAlgorithm) []byte {
+	return []byte{envelopeMagic[0], envelopeMagic[1], envelopeMagic[2], envelopeMagic[3], envelopeVersion, byte(algorithm)}
+}
+
+func envelopeAlgorithmOf(publicKey any) (envelopeAlgorithm, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return envelopeRsaOAEP, nil
+	case *ecdsa.PublicKey:
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return 0, err
+		}
+		return eciesAlgorithmOf(ecdhKey.Curve())
+	case *ecdh.PublicKey:
+		return eciesAlgorithmOf(key.Curve())
+	case nil:
+		return 0, toolkitError.ErrNilPublicKey
+	default:
+		return 0, toolkitError.ErrUnsupportedKeyType
+	}
+}
+
+func eciesAlgorithmOf(curve ecdh.Curve) (envelopeAlgorithm, error) {
+	switch curve {
+	case ecdh.P256():
+		return envelopeEciesP256, nil
+	case ecdh.P384():
+		return envelopeEciesP384, nil
+	case ecdh.P521():
+		return envelopeEciesP521, nil
+	case ecdh.X25519():
+		return envelopeEciesX25519, nil
+	default:
+		return 0, toolkitError.ErrUnsupportedKeyType
+	}
+}
+
+func eciesCurveOf(algorithm envelopeAlgorithm) ecdh.Curve {
+	switch algorithm {
+	case envelopeEciesP256:
+		return ecdh.P256()
+	case envelopeEciesP384:
+		return ecdh.P384()
+	case envelopeEciesP521:
+		return ecdh.P521()
+	default:
+		return ecdh.X25519()
+	}
+}
+
+// wrapKeyWithRsa 使用 RSA-OAEP 包装数据密钥，信封头部作为 OAEP label 防止算法被篡改
+func wrapKeyWithRsa(keyPair KeyPair, header, dataKey []byte) ([]byte, error) {
+	crypt, err := NewRsaEncryptWithOAEP(sha256.New(), header)
+	if err != nil {
+		return nil, err
+	}
+	return crypt.Encrypt(keyPair, dataKey)
+}
+
+func unwrapKeyWithRsa(keyPair KeyPair, header, wrappedKey []byte) ([]byte, error) {
+	crypt, err := NewRsaEncryptWithOAEP(sha256.New(), header)
+	if err != nil {
+		return nil, err
+	}
+	return crypt.Decrypt(keyPair, wrappedKey)
+}
+
+// wrapKeyWithEcies 生成临时密钥与接收方公钥协商，派生密钥加密密钥后包装数据密钥
+func wrapKeyWithEcies(keyPair KeyPair, header, dataKey []byte) ([]byte, error) {
+	recipient, err := loadEcdhPublicKey(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := recipient.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralPublic := ephemeral.PublicKey().Bytes()
+	crypt, err := eciesKeyCrypt(secret, ephemeralPublic, recipient.Bytes(), header)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := crypt.Encrypt(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(ephemeralPublic, wrapped...), nil
+}
+
+func unwrapKeyWithEcies(keyPair KeyPair, algorithm envelopeAlgorithm, header, wrappedKey []byte) ([]byte, error) {
+	privateKey, err := loadEcdhPrivateKey(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	curve := eciesCurveOf(algorithm)
+	if privateKey.Curve() != curve {
+		return nil, toolkitError.ErrKeyPairMismatch
+	}
+	// 临时公钥长度由曲线决定，使用接收方公钥长度推断
+	recipient := privateKey.PublicKey().Bytes()
+	if len(wrappedKey) < len(recipient) {
+		return nil, toolkitError.ErrInvalidEnvelope
+	}
+	ephemeralPublic := wrappedKey[:len(recipient)]
+	ephemeral, err := curve.NewPublicKey(ephemeralPublic)
+	if err != nil {
+		return nil, toolkitError.ErrInvalidEnvelope
+	}
+	secret, err := privateKey.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	crypt, err := eciesKeyCrypt(secret, ephemeralPublic, recipient, header)
+	if err != nil {
+		return nil, err
+	}
+	return crypt.Decrypt(wrappedKey[len(recipient):])
+}
+
+// eciesKeyCrypt 派生密钥加密密钥，salt 绑定双方公钥，info 绑定信封头部
+func eciesKeyCrypt(secret, ephemeralPublic, recipientPublic, header []byte) (*symmetric.AESEncrypt, error) {
+	salt := make([]byte, 0, len(ephemeralPublic)+len(recipientPublic))
+	salt = append(salt, ephemeralPublic...)
+	salt = append(salt, recipientPublic...)
+	kek, err := deriveKey(secret, salt, header, envelopeDataKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return symmetric.NewAESWithOption(kek, symmetric.AESOption{Mode: symmetric.AESModeGCM})
+}
+
+// loadEcdhPublicKey 将 ECDSA 或 X25519 公钥转换为 ECDH 公钥
+func loadEcdhPublicKey(keyPair KeyPair) (*ecdh.PublicKey, error) {
+	switch publicKey := keyPair.PublicKey().(type) {
+	case *ecdsa.PublicKey:
+		return publicKey.ECDH()
+	case *ecdh.PublicKey:
+		return publicKey, nil
+	case nil:
+		return nil, toolkitError.ErrNilPublicKey
+	default:
+		return nil, toolkitError.ErrUnsupportedKeyType
+	}
+}
+
+// loadEcdhPrivateKey 将 ECDSA 或 X25519 私钥转换为 ECDH 私钥
+func loadEcdhPrivateKey(keyPair KeyPair) (*ecdh.PrivateKey, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	switch privateKey := keyPair.PrivateKey().(type) {
+	case *ecdsa.PrivateKey:
+		return privateKey.ECDH()
+	case *ecdh.PrivateKey:
+		return privateKey, nil
+	case nil:
+		return nil, toolkitError.ErrNilPrivateKey
+	default:
+		return nil, toolkitError.ErrUnsupportedKeyType
+	}
+}
@@ -0,0 +1,92 @@
+package asymmetric
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	p384, err := NewEcdsaKeyManager(elliptic.P384()).Create()
+	if err != nil {
+		t.Fatalf("create p384 key pair: %v", err)
+	}
+	keyPairs := []KeyPair{newTestRsaKeyPair(t), newTestEcdsaKeyPair(t), p384, newTestX25519KeyPair(t)}
+	// 超过 RSA 模数长度的数据
+	raw := bytes.Repeat([]byte("large payload "), 4096)
+	var envelope CryptEncrypt = NewEnvelopeEncrypt()
+
+	for _, keyPair := range keyPairs {
+		publicPem := mustPublicPem(t, keyPair)
+		var publicOnly KeyPair
+		switch keyPair.(type) {
+		case RsaKeyPair:
+			publicOnly, err = NewEmptyRsaKeyManager().LoadPublicKey(publicPem)
+		case EcdsaKeyPair:
+			publicOnly, err = NewEmptyEcdsaKeyManager().LoadPublicKey(publicPem)
+		default:
+			publicOnly, err = NewX25519KeyManager().LoadPublicKey(publicPem)
+		}
+		if err != nil {
+			t.Fatalf("load public key: %v", err)
+		}
+
+		cipherData, err := envelope.Encrypt(publicOnly, raw)
+		if err != nil {
+			t.Fatalf("%T: encrypt: %v", keyPair, err)
+		}
+		plain, err := envelope.Decrypt(keyPair, cipherData)
+		if err != nil || !bytes.Equal(plain, raw) {
+			t.Fatalf("%T: unexpected decrypted data, %v", keyPair, err)
+		}
+		if _, err = envelope.Decrypt(publicOnly, cipherData); err == nil {
+			t.Fatalf("%T: expected decrypt without private key to fail", keyPair)
+		}
+
+		tampered := bytes.Clone(cipherData)
+		tampered[len(tampered)-1] ^= 0xFF
+		if _, err = envelope.Decrypt(keyPair, tampered); err == nil {
+			t.Fatalf("%T: expected tampered payload to fail", keyPair)
+		}
+	}
+}
+
+func TestEnvelopeRejectsInvalidInput(t *testing.T) {
+	envelope := NewEnvelopeEncrypt()
+	keyPair := newTestEcdsaKeyPair(t)
+	cipherData, err := envelope.Encrypt(keyPair, []byte("secret"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	if _, err = envelope.Decrypt(keyPair, []byte("short")); !errors.Is(err, toolkitError.ErrInvalidEnvelope) {
+		t.Fatalf("expected ErrInvalidEnvelope, got %v", err)
+	}
+	truncated := cipherData[:envelopeHeaderSize+10]
+	if _, err = envelope.Decrypt(keyPair, truncated); !errors.Is(err, toolkitError.ErrInvalidEnvelope) {
+		t.Fatalf("expected ErrInvalidEnvelope for truncated envelope, got %v", err)
+	}
+	p384, _ := NewEcdsaKeyManager(elliptic.P384()).Create()
+	if _, err = envelope.Decrypt(p384, cipherData); !errors.Is(err, toolkitError.ErrKeyPairMismatch) {
+		t.Fatalf("expected ErrKeyPairMismatch, got %v", err)
+	}
+	if _, err = envelope.Decrypt(newTestEcdsaKeyPair(t), cipherData); err == nil {
+		t.Fatal("expected decrypt with other key to fail")
+	}
+	if _, err = envelope.Encrypt(newTestEd25519KeyPair(t), []byte("secret")); !errors.Is(err, toolkitError.ErrUnsupportedKeyType) {
+		t.Fatalf("expected ErrUnsupportedKeyType, got %v", err)
+	}
+
+	base64Cipher, err := envelope.EncryptBase64(keyPair, base64.StdEncoding.EncodeToString([]byte("secret")))
+	if err != nil {
+		t.Fatalf("encrypt base64: %v", err)
+	}
+	base64Plain, err := envelope.DecryptBase64(keyPair, base64Cipher)
+	if err != nil || base64Plain != base64.StdEncoding.EncodeToString([]byte("secret")) {
+		t.Fatalf("unexpected base64 plain %s, %v", base64Plain, err)
+	}
+}
@@ -0,0 +1,146 @@
+package jose
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/util/json"
+)
+
+// Claims JWT 注册声明，自定义声明结构体可以嵌入 Claims
+type Claims struct {
+	// Issuer 签发者
+	Issuer string `json:"iss,omitempty"`
+	// Subject 主体
+	Subject string `json:"sub,omitempty"`
+	// Audience 受众
+	Audience Audience `json:"aud,omitempty"`
+	// ExpiresAt 过期时间
+	ExpiresAt NumericDate `json:"exp,omitempty"`
+	// NotBefore 生效时间
+	NotBefore NumericDate `json:"nbf,omitempty"`
+	// IssuedAt 签发时间
+	IssuedAt NumericDate `json:"iat,omitempty"`
+	// ID 令牌唯一标识
+	ID string `json:"jti,omitempty"`
+}
+
+// NumericDate JWT 时间声明，Unix 秒
+// RFC 7519 允许 NumericDate 带有小数部分，解析时截断为整数秒，输出时始终为整数
+type NumericDate int64
+
+// NewNumericDate 将时间转换为 NumericDate
+func NewNumericDate(t time.Time) NumericDate {
+	return NumericDate(t.Unix())
+}
+
+// Time 转换为 time.Time
+func (n NumericDate) Time() time.Time {
+	return time.Unix(int64(n), 0)
+}
+
+// UnmarshalJSON 同时支持整数和小数形式的 Unix 秒
+func (n *NumericDate) UnmarshalJSON(bs []byte) error {
+	var integer int64
+	if err := json.ParseBytesError(bs, &integer); err == nil {
+		*n = NumericDate(integer)
+		return nil
+	}
+	var float float64
+	if err := json.ParseBytesError(bs, &float); err != nil {
+		return err
+	}
+	if float >= math.MaxInt64 || float < math.MinInt64 {
+		return toolkitError.ErrMalformedToken
+	}
+	*n = NumericDate(math.Trunc(float))
+	return nil
+}
+
+// Audience JWT aud 声明，兼容单个字符串和字符串数组两种格式
+type Audience []string
+
+// NewClaims 创建以当前时间签发并在 ttl 后过期的注册声明
+func NewClaims(issuer, subject string, ttl time.Duration, audience ...string) Claims {
+	now := time.Now()
+	return Claims{
+		Issuer:    issuer,
+		Subject:   subject,
+		Audience:  audience,
+		ExpiresAt: NewNumericDate(now.Add(ttl)),
+		NotBefore: NewNumericDate(now),
+		IssuedAt:  NewNumericDate(now),
+	}
+}
+
+// MarshalJSON 只有一个受众时输出为字符串
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.ToBytesError(a[0])
+	}
+	return json.ToBytesError([]string(a))
+}
+
+// UnmarshalJSON 同时支持字符串和字符串数组
+func (a *Audience) UnmarshalJSON(bs []byte) error {
+	var single string
+	if err := json.ParseBytesError(bs, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.ParseBytesError(bs, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+// Contains 判断是否包含指定受众
+func (a Audience) Contains(audience string) bool {
+	return slices.Contains(a, audience)
+}
+
+// ValidateOption 表示校验 JWT 声明时使用的配置
+type ValidateOption struct {
+	// Issuer 期望的签发者，为空时不校验
+	Issuer string
+	// Audience 期望的受众，为空时不校验
+	Audience string
+	// ClockSkew 允许的时钟偏差，用于 exp、nbf 和 iat 校验
+	ClockSkew time.Duration
+	// RequireExpiration 是否要求令牌必须包含 exp
+	RequireExpiration bool
+	// Now 获取当前时间，为空时使用 time.Now
+	Now func() time.Time
+}
+
+// Validate 按配置校验注册声明
+func (c *Claims) Validate(option ValidateOption) error {
+	now := time.Now()
+	if option.Now != nil {
+		now = option.Now()
+	}
+	if c.ExpiresAt == 0 {
+		if option.RequireExpiration {
+			return toolkitError.ErrTokenMissingExpiration
+		}
+	} else if !now.Add(-option.ClockSkew).Before(c.ExpiresAt.Time()) {
+		return toolkitError.ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(option.ClockSkew).Before(c.NotBefore.Time()) {
+		return toolkitError.ErrTokenNotYetValid
+	}
+	if c.IssuedAt != 0 && now.Add(option.ClockSkew).Before(c.IssuedAt.Time()) {
+		return toolkitError.ErrTokenIssuedInFuture
+	}
+	if option.Issuer != "" && c.Issuer != option.Issuer {
+		return toolkitError.ErrInvalidIssuer
+	}
+	if option.Audience != "" && !c.Audience.Contains(option.Audience) {
+		return toolkitError.ErrInvalidAudience
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package jose
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/util/json"
+)
+
+func TestAudienceJSON(t *testing.T) {
+	bs, err := json.ToBytesError(Claims{Audience: Audience{"api"}})
+	if err != nil || string(bs) != `{"aud":"api"}` {
+		t.Fatalf("unexpected single audience json %s, %v", bs, err)
+	}
+	var claims Claims
+	if err = json.ParseBytesError([]byte(`{"aud":["api","web"]}`), &claims); err != nil || !claims.Audience.Contains("web") {
+		t.Fatalf("unexpected audience %v, %v", claims.Audience, err)
+	}
+	if err = json.ParseBytesError([]byte(`{"aud":"api"}`), &claims); err != nil || len(claims.Audience) != 1 {
+		t.Fatalf("unexpected audience %v, %v", claims.Audience, err)
+	}
+}
+
+func TestClaimsValidate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	option := ValidateOption{
+		Issuer:    "toolkit",
+		Audience:  "api",
+		ClockSkew: 30 * time.Second,
+		Now:       func() time.Time { return now },
+	}
+	valid := Claims{
+		Issuer:    "toolkit",
+		Audience:  Audience{"api", "web"},
+		ExpiresAt: NewNumericDate(now.Add(time.Minute)),
+		NotBefore: NewNumericDate(now),
+		IssuedAt:  NewNumericDate(now),
+	}
+	if err := valid.Validate(option); err != nil {
+		t.Fatalf("expected valid claims, got %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		modify func(c *Claims)
+		want   error
+	}{
+		{"expired", func(c *Claims) { c.ExpiresAt = NewNumericDate(now.Add(-time.Minute)) }, toolkitError.ErrTokenExpired},
+		{"not yet valid", func(c *Claims) { c.NotBefore = NewNumericDate(now.Add(time.Minute)) }, toolkitError.ErrTokenNotYetValid},
+		{"issued in future", func(c *Claims) { c.IssuedAt = NewNumericDate(now.Add(time.Minute)) }, toolkitError.ErrTokenIssuedInFuture},
+		{"issuer", func(c *Claims) { c.Issuer = "other" }, toolkitError.ErrInvalidIssuer},
+		{"audience", func(c *Claims) { c.Audience = Audience{"web"} }, toolkitError.ErrInvalidAudience},
+		{"within skew", func(c *Claims) { c.ExpiresAt = NewNumericDate(now.Add(-10 * time.Second)) }, nil},
+	}
+	for _, c := range cases {
+		claims := valid
+		c.modify(&claims)
+		if err := claims.Validate(option); !errors.Is(err, c.want) {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.want, err)
+		}
+	}
+
+	option.RequireExpiration = true
+	if err := (&Claims{}).Validate(option); !errors.Is(err, toolkitError.ErrTokenMissingExpiration) {
+		t.Fatalf("expected ErrTokenMissingExpiration, got %v", err)
+	}
+}
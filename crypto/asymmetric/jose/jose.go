@@ -0,0 +1,226 @@
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"math/big"
+
+	"github.com/acexy/golang-toolkit/crypto/asymmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+const (
+	// RS256 RSASSA-PKCS1-v1_5 SHA-256
+	RS256 Algorithm = "RS256"
+	// RS384 RSASSA-PKCS1-v1_5 SHA-384
+	RS384 Algorithm = "RS384"
+	// RS512 RSASSA-PKCS1-v1_5 SHA-512
+	RS512 Algorithm = "RS512"
+	// PS256 RSASSA-PSS SHA-256
+	PS256 Algorithm = "PS256"
+	// PS512 RSASSA-PSS SHA-512
+	PS512 Algorithm = "PS512"
+	// ES256 ECDSA P-256 SHA-256
+	ES256 Algorithm = "ES256"
+	// ES384 ECDSA P-384 SHA-384
+	ES384 Algorithm = "ES384"
+	// ES512 ECDSA P-521 SHA-512
+	ES512 Algorithm = "ES512"
+	// HS256 HMAC SHA-256
+	HS256 Algorithm = "HS256"
+)
+
+const (
+	// minRsaKeyBits JWS 要求 RSA 密钥至少 2048 位
+	minRsaKeyBits = 2048
+	// minHmacSecretSize HS256 要求密钥长度不小于摘要长度
+	minHmacSecretSize = sha256.Size
+)
+
+// Algorithm JWS 签名算法
+type Algorithm string
+
+// Header JWS 头部
+type Header struct {
+	// Algorithm 签名算法
+	Algorithm Algorithm `json:"alg"`
+	// Type 令牌类型，JWT 为 "JWT"
+	Type string `json:"typ,omitempty"`
+	// KeyID 签名密钥标识
+	KeyID string `json:"kid,omitempty"`
+	// ContentType 负载内容类型
+	ContentType string `json:"cty,omitempty"`
+	// Critical 必须被理解的扩展头，当前不支持任何扩展头
+	Critical []string `json:"crit,omitempty"`
+}
+
+// Key 表示 JOSE 签名或验签使用的密钥
+type Key struct {
+	// ID 密钥标识，签名时写入 kid 头，验签时按 kid 选择密钥
+	ID string
+	// Algorithm 密钥使用的签名算法，验签时令牌的 alg 必须与之一致
+	Algorithm Algorithm
+	// KeyPair RSA/ECDSA 算法使用的公私钥，签名需要私钥，验签只需要公钥
+	KeyPair asymmetric.KeyPair
+	// Secret HMAC 算法使用的共享密钥
+	Secret []byte
+}
+
+// signingMethod 绑定密钥后的签名验签实现
+type signingMethod interface {
+	sign(input []byte) ([]byte, error)
+	verify(input, signature []byte) error
+}
+
+// newSigningMethod 校验密钥与算法是否匹配并创建签名验签实现
+func newSigningMethod(key Key) (signingMethod, error) {
+	switch key.Algorithm {
+	case RS256, RS384, RS512, PS256, PS512:
+		return newRsaMethod(key)
+	case ES256, ES384, ES512:
+		return newEcdsaMethod(key)
+	case HS256:
+		if len(key.Secret) < minHmacSecretSize {
+			return nil, toolkitError.ErrBadKeyLength
+		}
+		// 复制密钥以避免外部修改
+		secret := make([]byte, len(key.Secret))
+		copy(secret, key.Secret)
+		return &hmacMethod{hashFunc: sha256.New, secret: secret}, nil
+	default:
+		return nil, toolkitError.ErrUnsupportedAlgorithm
+	}
+}
+
+// cryptSignMethod 基于 asymmetric.CryptSign 的签名验签实现
+type cryptSignMethod struct {
+	crypt   asymmetric.CryptSign
+	keyPair asymmetric.KeyPair
+}
+
+func (c *cryptSignMethod) sign(input []byte) ([]byte, error) {
+	return c.crypt.Sign(c.keyPair, input)
+}
+
+func (c *cryptSignMethod) verify(input, signature []byte) error {
+	return c.crypt.Verify(c.keyPair, input, signature)
+}
+
+func newRsaMethod(key Key) (signingMethod, error) {
+	if key.KeyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	publicKey, ok := key.KeyPair.PublicKey().(*rsa.PublicKey)
+	if !ok {
+		return nil, toolkitError.ErrNotRsaPublicKey
+	}
+	if publicKey.N.BitLen() < minRsaKeyBits {
+		return nil, toolkitError.ErrBadKeyLength
+	}
+	var crypt asymmetric.CryptSign
+	var err error
+	switch key.Algorithm {
+	case RS256:
+		crypt, err = asymmetric.NewRsaSignWithPKCS1(sha256.New(), crypto.SHA256)
+	case RS384:
+		crypt, err = asymmetric.NewRsaSignWithPKCS1(sha512.New384(), crypto.SHA384)
+	case RS512:
+		crypt, err = asymmetric.NewRsaSignWithPKCS1(sha512.New(), crypto.SHA512)
+	case PS256:
+		// RFC 7518 要求 PSS 盐长度与摘要长度一致
+		crypt, err = asymmetric.NewRsaSignWithPSSAndOptions(sha256.New(), crypto.SHA256, sha256.Size)
+	case PS512:
+		crypt, err = asymmetric.NewRsaSignWithPSSAndOptions(sha512.New(), crypto.SHA512, sha512.Size)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cryptSignMethod{crypt: crypt, keyPair: key.KeyPair}, nil
+}
+
+// ecdsaMethod JWS 中 ECDSA 签名为定长 R||S 格式而非 ASN.1 DER
+type ecdsaMethod struct {
+	crypt   *asymmetric.EcdsaSign
+	keyPair asymmetric.KeyPair
+	size    int
+}
+
+func newEcdsaMethod(key Key) (signingMethod, error) {
+	if key.KeyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	publicKey, ok := key.KeyPair.PublicKey().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, toolkitError.ErrNotEcdsaPublicKey
+	}
+	var curve elliptic.Curve
+	var hashFunc hash.Hash
+	switch key.Algorithm {
+	case ES256:
+		curve, hashFunc = elliptic.P256(), sha256.New()
+	case ES384:
+		curve, hashFunc = elliptic.P384(), sha512.New384()
+	case ES512:
+		curve, hashFunc = elliptic.P521(), sha512.New()
+	}
+	if publicKey.Curve != curve {
+		return nil, toolkitError.ErrAlgorithmMismatch
+	}
+	return &ecdsaMethod{
+		crypt:   asymmetric.NewEcdsaSign(hashFunc),
+		keyPair: key.KeyPair,
+		size:    (curve.Params().BitSize + 7) / 8,
+	}, nil
+}
+
+func (e *ecdsaMethod) sign(input []byte) ([]byte, error) {
+	r, s, err := e.crypt.SignRS(e.keyPair, input)
+	if err != nil {
+		return nil, err
+	}
+	signature := make([]byte, 2*e.size)
+	r.FillBytes(signature[:e.size])
+	s.FillBytes(signature[e.size:])
+	return signature, nil
+}
+
+func (e *ecdsaMethod) verify(input, signature []byte) error {
+	if len(signature) != 2*e.size {
+		return toolkitError.ErrVerifyFailed
+	}
+	r := new(big.Int).SetBytes(signature[:e.size])
+	s := new(big.Int).SetBytes(signature[e.size:])
+	ok, err := e.crypt.VerifyRS(e.keyPair, input, r, s)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return toolkitError.ErrVerifyFailed
+	}
+	return nil
+}
+
+type hmacMethod struct {
+	hashFunc func() hash.Hash
+	secret   []byte
+}
+
+func (h *hmacMethod) sign(input []byte) ([]byte, error) {
+	mac := hmac.New(h.hashFunc, h.secret)
+	mac.Write(input)
+	return mac.Sum(nil), nil
+}
+
+func (h *hmacMethod) verify(input, signature []byte) error {
+	expected, _ := h.sign(input)
+	if !hmac.Equal(expected, signature) {
+		return toolkitError.ErrVerifyFailed
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package jose
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"testing"
+
+	"github.com/acexy/golang-toolkit/crypto/asymmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestRsaKeyPair(t *testing.T) asymmetric.RsaKeyPair {
+	t.Helper()
+
+	keyPair, err := asymmetric.NewRsaKeyManager(2048).Create()
+	if err != nil {
+		t.Fatalf("create rsa key pair: %v", err)
+	}
+	return keyPair
+}
+
+func newTestEcdsaKeyPair(t *testing.T, curve elliptic.Curve) asymmetric.EcdsaKeyPair {
+	t.Helper()
+
+	keyPair, err := asymmetric.NewEcdsaKeyManager(curve).Create()
+	if err != nil {
+		t.Fatalf("create ecdsa key pair: %v", err)
+	}
+	return keyPair
+}
+
+func TestSigningMethods(t *testing.T) {
+	rsaKey := newTestRsaKeyPair(t)
+	secret := bytes.Repeat([]byte("s"), 32)
+	keys := []Key{
+		{Algorithm: RS256, KeyPair: rsaKey},
+		{Algorithm: RS384, KeyPair: rsaKey},
+		{Algorithm: RS512, KeyPair: rsaKey},
+		{Algorithm: PS256, KeyPair: rsaKey},
+		{Algorithm: PS512, KeyPair: rsaKey},
+		{Algorithm: ES256, KeyPair: newTestEcdsaKeyPair(t, elliptic.P256())},
+		{Algorithm: ES384, KeyPair: newTestEcdsaKeyPair(t, elliptic.P384())},
+		{Algorithm: ES512, KeyPair: newTestEcdsaKeyPair(t, elliptic.P521())},
+		{Algorithm: HS256, Secret: secret},
+	}
+	signatureSizes := map[Algorithm]int{ES256: 64, ES384: 96, ES512: 132, HS256: 32}
+	input := []byte("header.payload")
+
+	for _, key := range keys {
+		method, err := newSigningMethod(key)
+		if err != nil {
+			t.Fatalf("%s: new signing method: %v", key.Algorithm, err)
+		}
+		signature, err := method.sign(input)
+		if err != nil {
+			t.Fatalf("%s: sign: %v", key.Algorithm, err)
+		}
+		if size, ok := signatureSizes[key.Algorithm]; ok && len(signature) != size {
+			t.Fatalf("%s: expected %d byte signature, got %d", key.Algorithm, size, len(signature))
+		}
+		if err = method.verify(input, signature); err != nil {
+			t.Fatalf("%s: verify: %v", key.Algorithm, err)
+		}
+		if err = method.verify([]byte("header.tampered"), signature); err == nil {
+			t.Fatalf("%s: expected tampered input to fail", key.Algorithm)
+		}
+	}
+}
+
+func TestSigningMethodRejectsBadKeys(t *testing.T) {
+	cases := []struct {
+		key  Key
+		want error
+	}{
+		{Key{Algorithm: "none"}, toolkitError.ErrUnsupportedAlgorithm},
+		{Key{Algorithm: HS256, Secret: []byte("short")}, toolkitError.ErrBadKeyLength},
+		{Key{Algorithm: RS256}, toolkitError.ErrNilKeyPair},
+		{Key{Algorithm: RS256, KeyPair: newTestEcdsaKeyPair(t, elliptic.P256())}, toolkitError.ErrNotRsaPublicKey},
+		{Key{Algorithm: ES384, KeyPair: newTestEcdsaKeyPair(t, elliptic.P256())}, toolkitError.ErrAlgorithmMismatch},
+		{Key{Algorithm: ES256, KeyPair: newTestRsaKeyPair(t)}, toolkitError.ErrNotEcdsaPublicKey},
+	}
+	for _, c := range cases {
+		if _, err := newSigningMethod(c.key); !errors.Is(err, c.want) {
+			t.Fatalf("%s: expected %v, got %v", c.key.Algorithm, c.want, err)
+		}
+	}
+}
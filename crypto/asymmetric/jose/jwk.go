@@ -0,0 +1,458 @@
+package jose
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/acexy/golang-toolkit/crypto/asymmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/math/conversion"
+	"github.com/acexy/golang-toolkit/util/json"
+)
+
+const (
+	// KeyTypeRSA RSA 密钥
+	KeyTypeRSA = "RSA"
+	// KeyTypeEC 椭圆曲线密钥
+	KeyTypeEC = "EC"
+	// KeyTypeOKP Ed25519/X25519 等 RFC 8037 密钥
+	KeyTypeOKP = "OKP"
+)
+
+const (
+	// KeyUseSignature 密钥用于签名
+	KeyUseSignature = "sig"
+	// KeyUseEncryption 密钥用于加密
+	KeyUseEncryption = "enc"
+)
+
+// JWK JSON Web Key，支持 RSA、EC(P-256/P-384/P-521) 和 OKP(Ed25519/X25519)
+type JWK struct {
+	// KeyType 密钥类型
+	KeyType string `json:"kty"`
+	// KeyID 密钥标识
+	KeyID string `json:"kid,omitempty"`
+	// Use 密钥用途
+	Use string `json:"use,omitempty"`
+	// Algorithm 密钥适用的算法
+	Algorithm Algorithm `json:"alg,omitempty"`
+
+	// Curve EC/OKP 曲线名称
+	Curve string `json:"crv,omitempty"`
+	// X EC 公钥 x 坐标或 OKP 公钥
+	X string `json:"x,omitempty"`
+	// Y EC 公钥 y 坐标
+	Y string `json:"y,omitempty"`
+
+	// N RSA 模数
+	N string `json:"n,omitempty"`
+	// E RSA 公钥指数
+	E string `json:"e,omitempty"`
+
+	// D 私钥，RSA 为私钥指数，EC/OKP 为私钥标量或种子
+	D string `json:"d,omitempty"`
+	// P RSA 第一个素因子
+	P string `json:"p,omitempty"`
+	// Q RSA 第二个素因子
+	Q string `json:"q,omitempty"`
+	// DP RSA d mod (p-1)
+	DP string `json:"dp,omitempty"`
+	// DQ RSA d mod (q-1)
+	DQ string `json:"dq,omitempty"`
+	// QI RSA q 在模 p 下的逆元
+	QI string `json:"qi,omitempty"`
+}
+
+// ToPublicJWK 将公钥导出为 JWK
+func ToPublicJWK(keyPair asymmetric.KeyPair) (*JWK, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	switch publicKey := keyPair.PublicKey().(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			KeyType: KeyTypeRSA,
+			N:       encodeSegment(publicKey.N.Bytes()),
+			E:       encodeSegment(big.NewInt(int64(publicKey.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		curve, err := curveName(publicKey.Curve)
+		if err != nil {
+			return nil, err
+		}
+		ecdhKey, err := publicKey.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		// 未压缩点格式为 0x04 || X || Y
+		point := ecdhKey.Bytes()[1:]
+		return &JWK{
+			KeyType: KeyTypeEC,
+			Curve:   curve,
+			X:       encodeSegment(point[:len(point)/2]),
+			Y:       encodeSegment(point[len(point)/2:]),
+		}, nil
+	case ed25519.PublicKey:
+		return &JWK{KeyType: KeyTypeOKP, Curve: "Ed25519", X: encodeSegment(publicKey)}, nil
+	case *ecdh.PublicKey:
+		if publicKey.Curve() != ecdh.X25519() {
+			return nil, toolkitError.ErrUnsupportedKeyType
+		}
+		return &JWK{KeyType: KeyTypeOKP, Curve: "X25519", X: encodeSegment(publicKey.Bytes())}, nil
+	case nil:
+		return nil, toolkitError.ErrNilPublicKey
+	default:
+		return nil, toolkitError.ErrUnsupportedKeyType
+	}
+}
+
+// ToPrivateJWK 将公私钥导出为包含私钥的 JWK，私钥 JWK 不应对外发布
+func ToPrivateJWK(keyPair asymmetric.KeyPair) (*JWK, error) {
+	jwk, err := ToPublicJWK(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	switch privateKey := keyPair.PrivateKey().(type) {
+	case *rsa.PrivateKey:
+		if len(privateKey.Primes) != 2 {
+			return nil, toolkitError.ErrUnsupportedKeyType
+		}
+		// CRT 参数在本地计算，避免修改调用方持有的私钥
+		p, q := privateKey.Primes[0], privateKey.Primes[1]
+		one := big.NewInt(1)
+		jwk.D = encodeSegment(privateKey.D.Bytes())
+		jwk.P = encodeSegment(p.Bytes())
+		jwk.Q = encodeSegment(q.Bytes())
+		jwk.DP = encodeSegment(new(big.Int).Mod(privateKey.D, new(big.Int).Sub(p, one)).Bytes())
+		jwk.DQ = encodeSegment(new(big.Int).Mod(privateKey.D, new(big.Int).Sub(q, one)).Bytes())
+		jwk.QI = encodeSegment(new(big.Int).ModInverse(q, p).Bytes())
+	case *ecdsa.PrivateKey:
+		d, err := privateKey.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		jwk.D = encodeSegment(d)
+	case ed25519.PrivateKey:
+		jwk.D = encodeSegment(privateKey.Seed())
+	case *ecdh.PrivateKey:
+		jwk.D = encodeSegment(privateKey.Bytes())
+	case nil:
+		return nil, toolkitError.ErrNilPrivateKey
+	default:
+		return nil, toolkitError.ErrUnsupportedKeyType
+	}
+	return jwk, nil
+}
+
+// IsPrivate 判断 JWK 是否包含私钥
+func (j *JWK) IsPrivate() bool {
+	return j.D != ""
+}
+
+// Public 返回去除私钥成员后的 JWK 副本
+func (j *JWK) Public() *JWK {
+	return &JWK{
+		KeyType:   j.KeyType,
+		KeyID:     j.KeyID,
+		Use:       j.Use,
+		Algorithm: j.Algorithm,
+		Curve:     j.Curve,
+		X:         j.X,
+		Y:         j.Y,
+		N:         j.N,
+		E:         j.E,
+	}
+}
+
+// KeyPair 将 JWK 加载为公私钥，RSA/EC/Ed25519/X25519 分别对应 asymmetric 包中的 KeyPair 实现
+func (j *JWK) KeyPair() (asymmetric.KeyPair, error) {
+	publicKey, privateKey, err := j.rawKeys()
+	if err != nil {
+		return nil, err
+	}
+	pubPem, err := marshalPem("PUBLIC KEY", x509.MarshalPKIXPublicKey, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	priPem := ""
+	if privateKey != nil {
+		if priPem, err = marshalPem("PRIVATE KEY", x509.MarshalPKCS8PrivateKey, privateKey); err != nil {
+			return nil, err
+		}
+	}
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return asymmetric.NewEmptyRsaKeyManager().LoadKeyPair(pubPem, priPem)
+	case *ecdsa.PublicKey:
+		return asymmetric.NewEmptyEcdsaKeyManager().LoadKeyPair(pubPem, priPem)
+	case ed25519.PublicKey:
+		return asymmetric.NewEd25519KeyManager().LoadKeyPair(pubPem, priPem)
+	default:
+		return asymmetric.NewX25519KeyManager().LoadKeyPair(pubPem, priPem)
+	}
+}
+
+// Key 将 JWK 转换为签名验签使用的 Key，alg 为空时按密钥类型推断
+func (j *JWK) Key() (Key, error) {
+	algorithm := j.Algorithm
+	if algorithm == "" {
+		switch {
+		case j.KeyType == KeyTypeRSA:
+			algorithm = RS256
+		case j.KeyType == KeyTypeEC && j.Curve == "P-256":
+			algorithm = ES256
+		case j.KeyType == KeyTypeEC && j.Curve == "P-384":
+			algorithm = ES384
+		case j.KeyType == KeyTypeEC && j.Curve == "P-521":
+			algorithm = ES512
+		default:
+			return Key{}, toolkitError.ErrUnsupportedAlgorithm
+		}
+	}
+	keyPair, err := j.KeyPair()
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: j.KeyID, Algorithm: algorithm, KeyPair: keyPair}, nil
+}
+
+// Thumbprint 按 RFC 7638 计算 JWK 的 SHA-256 指纹
+func (j *JWK) Thumbprint() ([]byte, error) {
+	var members any
+	switch j.KeyType {
+	case KeyTypeRSA:
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.KeyType, j.N}
+	case KeyTypeEC:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{j.Curve, j.KeyType, j.X, j.Y}
+	case KeyTypeOKP:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Curve, j.KeyType, j.X}
+	default:
+		return nil, toolkitError.ErrUnsupportedKeyType
+	}
+	// 结构体字段已按字典序排列，JSON 输出不含空白
+	bs, err := json.ToBytesError(members)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(bs)
+	return sum[:], nil
+}
+
+// ThumbprintBase64 计算 RFC 7638 指纹并以 Base64URL 编码返回，常用作 kid
+func (j *JWK) ThumbprintBase64() (string, error) {
+	thumbprint, err := j.Thumbprint()
+	if err != nil {
+		return "", err
+	}
+	return encodeSegment(thumbprint), nil
+}
+
+// rawKeys 解析 JWK 中的原始公私钥，不包含私钥时 privateKey 为空
+func (j *JWK) rawKeys() (publicKey, privateKey any, err error) {
+	switch j.KeyType {
+	case KeyTypeRSA:
+		return j.rsaKeys()
+	case KeyTypeEC:
+		return j.ecdsaKeys()
+	case KeyTypeOKP:
+		return j.okpKeys()
+	default:
+		return nil, nil, toolkitError.ErrUnsupportedKeyType
+	}
+}
+
+func (j *JWK) rsaKeys() (any, any, error) {
+	n, err := decodeInt(j.N)
+	if err != nil {
+		return nil, nil, err
+	}
+	e, err := decodeInt(j.E)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, nil, fmt.Errorf("%w: rsa exponent too large", toolkitError.ErrInvalidJWK)
+	}
+	publicKey := &rsa.PublicKey{N: n, E: int(e.Int64())}
+	if !j.IsPrivate() {
+		return publicKey, nil, nil
+	}
+	d, err := decodeInt(j.D)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := decodeInt(j.P)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := decodeInt(j.Q)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey := &rsa.PrivateKey{PublicKey: *publicKey, D: d, Primes: []*big.Int{p, q}}
+	if err = privateKey.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrInvalidJWK, err)
+	}
+	privateKey.Precompute()
+	return publicKey, privateKey, nil
+}
+
+func (j *JWK) ecdsaKeys() (any, any, error) {
+	var curve elliptic.Curve
+	switch j.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, nil, toolkitError.ErrUnsupportedKeyType
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	x, err := decodeFixed(j.X, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	y, err := decodeFixed(j.Y, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	point := append(append([]byte{0x04}, x...), y...)
+	publicKey, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrInvalidJWK, err)
+	}
+	if !j.IsPrivate() {
+		return publicKey, nil, nil
+	}
+	d, err := decodeFixed(j.D, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := ecdsa.ParseRawPrivateKey(curve, d)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrInvalidJWK, err)
+	}
+	return publicKey, privateKey, nil
+}
+
+func (j *JWK) okpKeys() (any, any, error) {
+	switch j.Curve {
+	case "Ed25519":
+		x, err := decodeFixed(j.X, ed25519.PublicKeySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		publicKey := ed25519.PublicKey(x)
+		if !j.IsPrivate() {
+			return publicKey, nil, nil
+		}
+		seed, err := decodeFixed(j.D, ed25519.SeedSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return publicKey, ed25519.NewKeyFromSeed(seed), nil
+	case "X25519":
+		x, err := decodeSegment(j.X)
+		if err != nil {
+			return nil, nil, err
+		}
+		publicKey, err := ecdh.X25519().NewPublicKey(x)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrInvalidJWK, err)
+		}
+		if !j.IsPrivate() {
+			return publicKey, nil, nil
+		}
+		d, err := decodeSegment(j.D)
+		if err != nil {
+			return nil, nil, err
+		}
+		privateKey, err := ecdh.X25519().NewPrivateKey(d)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrInvalidJWK, err)
+		}
+		return publicKey, privateKey, nil
+	default:
+		return nil, nil, toolkitError.ErrUnsupportedKeyType
+	}
+}
+
+func curveName(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return "P-256", nil
+	case elliptic.P384():
+		return "P-384", nil
+	case elliptic.P521():
+		return "P-521", nil
+	default:
+		return "", toolkitError.ErrUnsupportedKeyType
+	}
+}
+
+func marshalPem(pemType string, marshal func(any) ([]byte, error), key any) (string, error) {
+	der, err := marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der})), nil
+}
+
+func encodeSegment(bs []byte) string {
+	return base64.RawURLEncoding.EncodeToString(bs)
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	if segment == "" {
+		return nil, fmt.Errorf("%w: missing member", toolkitError.ErrInvalidJWK)
+	}
+	bs, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", toolkitError.ErrInvalidJWK, err)
+	}
+	return bs, nil
+}
+
+// decodeFixed 解码定长成员，RFC 7518 要求 EC 坐标和私钥按曲线长度补齐
+func decodeFixed(segment string, size int) ([]byte, error) {
+	bs, err := decodeSegment(segment)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) != size {
+		return nil, fmt.Errorf("%w: unexpected member length %d", toolkitError.ErrInvalidJWK, len(bs))
+	}
+	return bs, nil
+}
+
+func decodeInt(segment string) (*big.Int, error) {
+	bs, err := decodeSegment(segment)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bs), nil
+}
@@ -0,0 +1,124 @@
+package jose
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/acexy/golang-toolkit/crypto/asymmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/util/json"
+)
+
+func TestJWKRoundTrip(t *testing.T) {
+	ed25519Key, err := asymmetric.NewEd25519KeyManager().Create()
+	if err != nil {
+		t.Fatalf("create ed25519 key pair: %v", err)
+	}
+	x25519Key, err := asymmetric.NewX25519KeyManager().Create()
+	if err != nil {
+		t.Fatalf("create x25519 key pair: %v", err)
+	}
+	keyPairs := []asymmetric.KeyPair{
+		newTestRsaKeyPair(t),
+		newTestEcdsaKeyPair(t, elliptic.P256()),
+		newTestEcdsaKeyPair(t, elliptic.P521()),
+		ed25519Key,
+		x25519Key,
+	}
+
+	for _, keyPair := range keyPairs {
+		privateJWK, err := ToPrivateJWK(keyPair)
+		if err != nil {
+			t.Fatalf("export private jwk: %v", err)
+		}
+		bs, err := json.ToBytesError(privateJWK)
+		if err != nil {
+			t.Fatalf("marshal jwk: %v", err)
+		}
+		var parsed JWK
+		if err = json.ParseBytesError(bs, &parsed); err != nil {
+			t.Fatalf("unmarshal jwk: %v", err)
+		}
+		loaded, err := parsed.KeyPair()
+		if err != nil {
+			t.Fatalf("%s %s: load jwk: %v", parsed.KeyType, parsed.Curve, err)
+		}
+		if loaded.PrivateKey() == nil {
+			t.Fatalf("%s %s: expected private key", parsed.KeyType, parsed.Curve)
+		}
+		wantPem, _ := keyPair.ToPrivatePem()
+		gotPem, _ := loaded.ToPrivatePem()
+		if wantPem != gotPem {
+			t.Fatalf("%s %s: private key changed after round trip", parsed.KeyType, parsed.Curve)
+		}
+
+		publicJWK, err := ToPublicJWK(keyPair)
+		if err != nil || publicJWK.IsPrivate() || *publicJWK != *privateJWK.Public() {
+			t.Fatalf("unexpected public jwk %+v, %v", publicJWK, err)
+		}
+		publicOnly, err := publicJWK.KeyPair()
+		if err != nil || publicOnly.PrivateKey() != nil {
+			t.Fatalf("expected public only key pair, %v", err)
+		}
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// RFC 8037 附录 A.3 的 Ed25519 示例
+	jwk := &JWK{KeyType: KeyTypeOKP, Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo", KeyID: "ignored"}
+	thumbprint, err := jwk.ThumbprintBase64()
+	if err != nil || thumbprint != "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k" {
+		t.Fatalf("unexpected ed25519 thumbprint %s, %v", thumbprint, err)
+	}
+
+	ecJWK, err := ToPrivateJWK(newTestEcdsaKeyPair(t, elliptic.P256()))
+	if err != nil {
+		t.Fatalf("export ec jwk: %v", err)
+	}
+	got, err := ecJWK.Thumbprint()
+	if err != nil {
+		t.Fatalf("ec thumbprint: %v", err)
+	}
+	want := sha256.Sum256([]byte(`{"crv":"P-256","kty":"EC","x":"` + ecJWK.X + `","y":"` + ecJWK.Y + `"}`))
+	if string(got) != string(want[:]) {
+		t.Fatal("unexpected ec thumbprint")
+	}
+	publicThumbprint, _ := ecJWK.Public().Thumbprint()
+	if string(publicThumbprint) != string(got) {
+		t.Fatal("expected private and public jwk to share thumbprint")
+	}
+	if _, err = (&JWK{KeyType: "oct"}).Thumbprint(); !errors.Is(err, toolkitError.ErrUnsupportedKeyType) {
+		t.Fatalf("expected ErrUnsupportedKeyType, got %v", err)
+	}
+}
+
+func TestJWKRejectsInvalidKeys(t *testing.T) {
+	ecJWK, err := ToPublicJWK(newTestEcdsaKeyPair(t, elliptic.P256()))
+	if err != nil {
+		t.Fatalf("export ec jwk: %v", err)
+	}
+	offCurve := *ecJWK
+	offCurve.X, offCurve.Y = offCurve.Y, offCurve.X
+	if _, err = offCurve.KeyPair(); !errors.Is(err, toolkitError.ErrInvalidJWK) {
+		t.Fatalf("expected ErrInvalidJWK for point off curve, got %v", err)
+	}
+	missing := *ecJWK
+	missing.Y = ""
+	if _, err = missing.KeyPair(); !errors.Is(err, toolkitError.ErrInvalidJWK) {
+		t.Fatalf("expected ErrInvalidJWK for missing member, got %v", err)
+	}
+
+	rsaJWK, err := ToPrivateJWK(newTestRsaKeyPair(t))
+	if err != nil {
+		t.Fatalf("export rsa jwk: %v", err)
+	}
+	rsaJWK.D = rsaJWK.DP
+	if _, err = rsaJWK.KeyPair(); !errors.Is(err, toolkitError.ErrInvalidJWK) {
+		t.Fatalf("expected ErrInvalidJWK for bad rsa private key, got %v", err)
+	}
+	if _, err = (&JWK{KeyType: "oct"}).KeyPair(); !errors.Is(err, toolkitError.ErrUnsupportedKeyType) {
+		t.Fatalf("expected ErrUnsupportedKeyType, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package jose
+
+import (
+	"errors"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/util/json"
+)
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	// Keys 密钥列表
+	Keys []*JWK `json:"keys"`
+}
+
+// ParseJWKS 从 JSON 解析 JWKS
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var set JWKS
+	if err := json.ParseBytesError(data, &set); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// Lookup 按 kid 查找密钥
+func (s *JWKS) Lookup(kid string) (*JWK, bool) {
+	for _, key := range s.Keys {
+		if key.KeyID == kid {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// Add 添加密钥，kid 已存在时替换原有密钥
+func (s *JWKS) Add(jwk *JWK) {
+	for i, key := range s.Keys {
+		if key.KeyID == jwk.KeyID {
+			s.Keys[i] = jwk
+			return
+		}
+	}
+	s.Keys = append(s.Keys, jwk)
+}
+
+// Public 返回只包含公钥成员的副本，适合在 well-known 地址发布
+func (s *JWKS) Public() *JWKS {
+	public := &JWKS{Keys: make([]*JWK, 0, len(s.Keys))}
+	for _, key := range s.Keys {
+		public.Keys = append(public.Keys, key.Public())
+	}
+	return public
+}
+
+// Verifier 使用集合中的签名密钥创建验证器
+// use 为 enc 的密钥以及算法或密钥类型不受支持的密钥会被跳过
+func (s *JWKS) Verifier(option ValidateOption) (*Verifier, error) {
+	verifier := &Verifier{option: option}
+	for _, jwk := range s.Keys {
+		if jwk.Use == KeyUseEncryption {
+			continue
+		}
+		key, err := jwk.Key()
+		if err == nil {
+			err = verifier.AddKey(key)
+		}
+		if errors.Is(err, toolkitError.ErrUnsupportedAlgorithm) || errors.Is(err, toolkitError.ErrUnsupportedKeyType) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return verifier, nil
+}
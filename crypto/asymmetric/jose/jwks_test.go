@@ -0,0 +1,83 @@
+package jose
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acexy/golang-toolkit/crypto/asymmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/util/json"
+)
+
+func TestJWKSPublishAndVerify(t *testing.T) {
+	rsaKey := newTestRsaKeyPair(t)
+	ecKey := newTestEcdsaKeyPair(t, elliptic.P384())
+	x25519Key, err := asymmetric.NewX25519KeyManager().Create()
+	if err != nil {
+		t.Fatalf("create x25519 key pair: %v", err)
+	}
+
+	set := &JWKS{}
+	for _, keyPair := range []asymmetric.KeyPair{rsaKey, ecKey, x25519Key} {
+		jwk, err := ToPrivateJWK(keyPair)
+		if err != nil {
+			t.Fatalf("export jwk: %v", err)
+		}
+		if jwk.KeyID, err = jwk.ThumbprintBase64(); err != nil {
+			t.Fatalf("thumbprint: %v", err)
+		}
+		set.Add(jwk)
+	}
+	set.Keys[2].Use = KeyUseEncryption
+
+	published, err := json.ToBytesError(set.Public())
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+	if strings.Contains(string(published), `"d"`) {
+		t.Fatalf("expected published jwks without private members: %s", published)
+	}
+	parsed, err := ParseJWKS(published)
+	if err != nil || len(parsed.Keys) != 3 {
+		t.Fatalf("unexpected parsed jwks %v, %v", parsed, err)
+	}
+	if _, ok := parsed.Lookup(set.Keys[1].KeyID); !ok {
+		t.Fatal("expected lookup by kid")
+	}
+	if _, ok := parsed.Lookup("missing"); ok {
+		t.Fatal("unexpected lookup result")
+	}
+
+	verifier, err := parsed.Verifier(ValidateOption{})
+	if err != nil {
+		t.Fatalf("jwks verifier: %v", err)
+	}
+	signer, err := NewSigner(Key{ID: set.Keys[1].KeyID, Algorithm: ES384, KeyPair: ecKey})
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	token, _ := signer.Sign(NewClaims("idp", "user", time.Minute))
+	if _, err = verifier.Verify(token, nil); err != nil {
+		t.Fatalf("verify with jwks: %v", err)
+	}
+	rsaSigner, _ := NewSigner(Key{ID: set.Keys[0].KeyID, Algorithm: RS256, KeyPair: rsaKey})
+	token, _ = rsaSigner.Sign(NewClaims("idp", "user", time.Minute))
+	if _, err = verifier.Verify(token, nil); err != nil {
+		t.Fatalf("verify rsa with jwks: %v", err)
+	}
+
+	// 同一 kid 再次添加时替换原有密钥
+	replacement := set.Keys[0].Public()
+	set.Add(replacement)
+	if len(set.Keys) != 3 || set.Keys[0] != replacement {
+		t.Fatal("expected jwk with same kid to be replaced")
+	}
+
+	parsed.Keys[0].N = "AQAB"
+	if _, err = parsed.Verifier(ValidateOption{}); !errors.Is(err, toolkitError.ErrBadKeyLength) {
+		t.Fatalf("expected ErrBadKeyLength for weak rsa key, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package jose
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/util/json"
+)
+
+// Signer 使用单个密钥签发 JWS/JWT
+type Signer struct {
+	key    Key
+	method signingMethod
+}
+
+// NewSigner 创建签发器，非对称算法的 KeyPair 不包含私钥时返回 ErrSigningKeyNotPrivate
+func NewSigner(key Key) (*Signer, error) {
+	method, err := newSigningMethod(key)
+	if err != nil {
+		return nil, err
+	}
+	if key.KeyPair != nil && key.KeyPair.PrivateKey() == nil {
+		return nil, toolkitError.ErrSigningKeyNotPrivate
+	}
+	return &Signer{key: key, method: method}, nil
+}
+
+// Sign 将 claims 序列化为 JSON 并签发 JWT，claims 可以是 Claims 或嵌入 Claims 的自定义结构体
+func (s *Signer) Sign(claims any) (string, error) {
+	payload, err := json.ToBytesError(claims)
+	if err != nil {
+		return "", err
+	}
+	return s.sign(Header{Type: "JWT"}, payload)
+}
+
+// SignPayload 对任意负载签发 JWS 紧凑序列化格式
+func (s *Signer) SignPayload(payload []byte) (string, error) {
+	return s.sign(Header{}, payload)
+}
+
+func (s *Signer) sign(header Header, payload []byte) (string, error) {
+	header.Algorithm = s.key.Algorithm
+	header.KeyID = s.key.ID
+	headerBytes, err := json.ToBytesError(header)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := s.method.sign([]byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// verifyKey 验签密钥与绑定的签名实现
+type verifyKey struct {
+	key    Key
+	method signingMethod
+}
+
+// Verifier 使用一组密钥验证 JWS/JWT，按令牌中的 kid 选择密钥
+type Verifier struct {
+	mu     sync.RWMutex
+	keys   []verifyKey
+	option ValidateOption
+}
+
+// NewVerifier 创建验证器，option 用于 JWT 声明校验
+func NewVerifier(option ValidateOption, keys ...Key) (*Verifier, error) {
+	verifier := &Verifier{option: option}
+	for _, key := range keys {
+		if err := verifier.AddKey(key); err != nil {
+			return nil, err
+		}
+	}
+	return verifier, nil
+}
+
+// AddKey 添加验签密钥，用于密钥轮换时同时信任新旧密钥
+func (v *Verifier) AddKey(key Key) error {
+	method, err := newSigningMethod(key)
+	if err != nil {
+		return err
+	}
+	v.mu.Lock()
+	v.keys = append(v.keys, verifyKey{key: key, method: method})
+	v.mu.Unlock()
+	return nil
+}
+
+// Verify 验证 JWT 签名和注册声明，并将负载解析到 claims，claims 为空时只做校验
+func (v *Verifier) Verify(token string, claims any) (*Header, error) {
+	header, payload, err := v.VerifyPayload(token)
+	if err != nil {
+		return nil, err
+	}
+	var registered Claims
+	if err = json.ParseBytesError(payload, &registered); err != nil {
+		return nil, fmt.Errorf("%w: %w", toolkitError.ErrMalformedToken, err)
+	}
+	if err = registered.Validate(v.option); err != nil {
+		return nil, err
+	}
+	if claims != nil {
+		if err = json.ParseBytesError(payload, claims); err != nil {
+			return nil, err
+		}
+	}
+	return header, nil
+}
+
+// VerifyPayload 验证 JWS 签名并返回头部和原始负载，不校验 JWT 声明
+func (v *Verifier) VerifyPayload(token string) (*Header, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, toolkitError.ErrMalformedToken
+	}
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrMalformedToken, err)
+	}
+	var header Header
+	if err = json.ParseBytesError(headerBytes, &header); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrMalformedToken, err)
+	}
+	if len(header.Critical) > 0 {
+		return nil, nil, toolkitError.ErrUnsupportedCriticalHeader
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrMalformedToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", toolkitError.ErrMalformedToken, err)
+	}
+
+	candidates, err := v.selectKeys(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	input := []byte(token[:len(parts[0])+1+len(parts[1])])
+	for _, candidate := range candidates {
+		if err = candidate.method.verify(input, signature); err == nil {
+			return &header, payload, nil
+		}
+	}
+	return nil, nil, toolkitError.ErrVerifyFailed
+}
+
+// selectKeys 存在 kid 时只使用对应密钥，否则尝试所有算法一致的密钥
+// 令牌的 alg 必须与密钥配置的算法一致，避免算法混淆攻击
+func (v *Verifier) selectKeys(header Header) ([]verifyKey, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	candidates := make([]verifyKey, 0, 1)
+	for _, key := range v.keys {
+		if header.KeyID != "" {
+			if key.key.ID != header.KeyID {
+				continue
+			}
+			if key.key.Algorithm != header.Algorithm {
+				return nil, toolkitError.ErrAlgorithmMismatch
+			}
+			return []verifyKey{key}, nil
+		}
+		if key.key.Algorithm == header.Algorithm {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, toolkitError.ErrSigningKeyNotFound
+	}
+	return candidates, nil
+}
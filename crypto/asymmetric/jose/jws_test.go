@@ -0,0 +1,183 @@
+package jose
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acexy/golang-toolkit/crypto/asymmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+type testClaims struct {
+	Claims
+	Role string `json:"role"`
+}
+
+func publicOnly(t *testing.T, keyPair asymmetric.KeyPair) asymmetric.EcdsaKeyPair {
+	t.Helper()
+
+	publicPem, err := keyPair.ToPublicPem()
+	if err != nil {
+		t.Fatalf("export public pem: %v", err)
+	}
+	loaded, err := asymmetric.NewEmptyEcdsaKeyManager().LoadPublicKey(publicPem)
+	if err != nil {
+		t.Fatalf("load public key: %v", err)
+	}
+	return loaded
+}
+
+func TestSignAndVerifyJWT(t *testing.T) {
+	keyPair := newTestEcdsaKeyPair(t, elliptic.P256())
+	signer, err := NewSigner(Key{ID: "k1", Algorithm: ES256, KeyPair: keyPair})
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	token, err := signer.Sign(testClaims{Claims: NewClaims("toolkit", "user-1", time.Minute, "api"), Role: "admin"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	verifier, err := NewVerifier(ValidateOption{Issuer: "toolkit", Audience: "api", RequireExpiration: true},
+		Key{ID: "k1", Algorithm: ES256, KeyPair: publicOnly(t, keyPair)})
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	var got testClaims
+	header, err := verifier.Verify(token, &got)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if header.KeyID != "k1" || header.Type != "JWT" || got.Subject != "user-1" || got.Role != "admin" {
+		t.Fatalf("unexpected header %+v or claims %+v", header, got)
+	}
+
+	parts := strings.Split(token, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-2","role":"admin"}`)) + "." + parts[2]
+	if _, err = verifier.Verify(forged, nil); !errors.Is(err, toolkitError.ErrVerifyFailed) {
+		t.Fatalf("expected ErrVerifyFailed, got %v", err)
+	}
+	if _, err = verifier.Verify("a.b", nil); !errors.Is(err, toolkitError.ErrMalformedToken) {
+		t.Fatalf("expected ErrMalformedToken, got %v", err)
+	}
+
+	expired, _ := signer.Sign(Claims{Issuer: "toolkit", Audience: Audience{"api"}, ExpiresAt: NewNumericDate(time.Now().Add(-time.Hour))})
+	if _, err = verifier.Verify(expired, nil); !errors.Is(err, toolkitError.ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestNewSignerRequiresPrivateKey(t *testing.T) {
+	keyPair := newTestEcdsaKeyPair(t, elliptic.P256())
+	if _, err := NewSigner(Key{Algorithm: ES256, KeyPair: publicOnly(t, keyPair)}); !errors.Is(err, toolkitError.ErrSigningKeyNotPrivate) {
+		t.Fatalf("expected ErrSigningKeyNotPrivate, got %v", err)
+	}
+
+	publicJWK, err := ToPublicJWK(newTestRsaKeyPair(t))
+	if err != nil {
+		t.Fatalf("export public jwk: %v", err)
+	}
+	key, err := publicJWK.Key()
+	if err != nil {
+		t.Fatalf("load public jwk: %v", err)
+	}
+	if _, err = NewSigner(key); !errors.Is(err, toolkitError.ErrSigningKeyNotPrivate) {
+		t.Fatalf("expected ErrSigningKeyNotPrivate for public jwk, got %v", err)
+	}
+}
+
+func TestVerifyFractionalNumericDate(t *testing.T) {
+	keyPair := newTestEcdsaKeyPair(t, elliptic.P256())
+	signer, err := NewSigner(Key{ID: "k1", Algorithm: ES256, KeyPair: keyPair})
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	// 第三方签发的令牌可能使用带小数的 NumericDate
+	exp := time.Now().Add(time.Hour).Unix()
+	payload := fmt.Sprintf(`{"sub":"user-1","role":"admin","iat":1700000000.5,"nbf":1700000000.25,"exp":%d.75}`, exp)
+	token, err := signer.SignPayload([]byte(payload))
+	if err != nil {
+		t.Fatalf("sign payload: %v", err)
+	}
+	verifier, err := NewVerifier(ValidateOption{}, Key{ID: "k1", Algorithm: ES256, KeyPair: keyPair})
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	var got testClaims
+	if _, err = verifier.Verify(token, &got); err != nil {
+		t.Fatalf("verify fractional numeric date: %v", err)
+	}
+	if got.IssuedAt != 1700000000 || got.NotBefore != 1700000000 || got.ExpiresAt != NumericDate(exp) || got.Role != "admin" {
+		t.Fatalf("unexpected claims %+v", got)
+	}
+
+	overflow, _ := signer.SignPayload([]byte(`{"exp":1e300}`))
+	if _, err = verifier.Verify(overflow, nil); !errors.Is(err, toolkitError.ErrMalformedToken) {
+		t.Fatalf("expected ErrMalformedToken for out of range exp, got %v", err)
+	}
+}
+
+func TestVerifierKeySelection(t *testing.T) {
+	oldKey := Key{ID: "old", Algorithm: HS256, Secret: bytes.Repeat([]byte("o"), 32)}
+	newKey := Key{ID: "new", Algorithm: HS256, Secret: bytes.Repeat([]byte("n"), 32)}
+	oldSigner, _ := NewSigner(oldKey)
+	newSigner, _ := NewSigner(newKey)
+	verifier, err := NewVerifier(ValidateOption{}, oldKey, newKey)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	for _, signer := range []*Signer{oldSigner, newSigner} {
+		token, err := signer.SignPayload([]byte("payload"))
+		if err != nil {
+			t.Fatalf("sign payload: %v", err)
+		}
+		header, payload, err := verifier.VerifyPayload(token)
+		if err != nil || header.KeyID != signer.key.ID || string(payload) != "payload" {
+			t.Fatalf("unexpected verify result %+v, %q, %v", header, payload, err)
+		}
+	}
+
+	// 未携带 kid 时尝试所有算法一致的密钥
+	anonymous, _ := NewSigner(Key{Algorithm: HS256, Secret: newKey.Secret})
+	token, _ := anonymous.SignPayload([]byte("payload"))
+	if _, _, err = verifier.VerifyPayload(token); err != nil {
+		t.Fatalf("expected token without kid to verify, got %v", err)
+	}
+
+	unknown, _ := NewSigner(Key{ID: "unknown", Algorithm: HS256, Secret: newKey.Secret})
+	token, _ = unknown.SignPayload([]byte("payload"))
+	if _, _, err = verifier.VerifyPayload(token); !errors.Is(err, toolkitError.ErrSigningKeyNotFound) {
+		t.Fatalf("expected ErrSigningKeyNotFound, got %v", err)
+	}
+}
+
+func TestVerifierRejectsAlgorithmConfusion(t *testing.T) {
+	keyPair := newTestRsaKeyPair(t)
+	verifier, err := NewVerifier(ValidateOption{}, Key{ID: "rsa", Algorithm: RS256, KeyPair: keyPair})
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	// 使用 RSA 公钥 PEM 作为 HMAC 密钥伪造令牌
+	publicPem, _ := keyPair.ToPublicPem()
+	forger, _ := NewSigner(Key{ID: "rsa", Algorithm: HS256, Secret: []byte(publicPem)})
+	token, _ := forger.Sign(Claims{Subject: "attacker"})
+	if _, err = verifier.Verify(token, nil); !errors.Is(err, toolkitError.ErrAlgorithmMismatch) {
+		t.Fatalf("expected ErrAlgorithmMismatch, got %v", err)
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"rsa","crit":["exp"]}`))
+	if _, _, err = verifier.VerifyPayload(header + ".e30."); !errors.Is(err, toolkitError.ErrUnsupportedCriticalHeader) {
+		t.Fatalf("expected ErrUnsupportedCriticalHeader, got %v", err)
+	}
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	if _, _, err = verifier.VerifyPayload(none + ".e30."); !errors.Is(err, toolkitError.ErrSigningKeyNotFound) {
+		t.Fatalf("expected ErrSigningKeyNotFound for alg none, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package asymmetric
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"slices"
+	"sync"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// 签名信封格式：version(1) | keyIDLength(1) | keyID | signature
+const (
+	signatureEnvelopeVersion byte = 1
+	maxKeyIDLength                = 255
+)
+
+// KeyringEntry 表示密钥环中的一个密钥版本
+type KeyringEntry struct {
+	// ID 密钥标识，写入签名信封用于验签时选择密钥，长度不超过 255 字节
+	ID string
+	// KeyPair 公私钥，只用于验签时可以只包含公钥
+	KeyPair KeyPair
+	// Sign 签名实现，为空时按密钥类型选择：RSA 使用 PSS SHA256，ECDSA 使用 SHA256，Ed25519 使用 Ed25519Sign
+	Sign CryptSign
+	// ActivateAt 生效时间，为零值时立即生效
+	ActivateAt time.Time
+	// ExpireAt 过期时间，为零值时永不过期，过期后既不用于签名也不用于验签
+	ExpireAt time.Time
+}
+
+// activeAt 判断密钥在指定时间是否有效
+func (k *KeyringEntry) activeAt(now time.Time) bool {
+	if !k.ActivateAt.IsZero() && now.Before(k.ActivateAt) {
+		return false
+	}
+	return k.ExpireAt.IsZero() || now.Before(k.ExpireAt)
+}
+
+// Keyring 管理多个版本的签名密钥，用于密钥轮换
+// 签名使用当前有效且生效时间最晚的密钥，并将密钥标识写入签名信封；
+// 验签时按信封中的密钥标识选择密钥，没有标识时尝试所有有效密钥
+type Keyring struct {
+	mu      sync.RWMutex
+	entries []*KeyringEntry
+}
+
+// NewKeyring 创建密钥环
+func NewKeyring(entries ...KeyringEntry) (*Keyring, error) {
+	keyring := &Keyring{}
+	for _, entry := range entries {
+		if err := keyring.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return keyring, nil
+}
+
+// Add 添加密钥版本，密钥标识不能重复
+func (k *Keyring) Add(entry KeyringEntry) error {
+	if entry.ID == "" || len(entry.ID) > maxKeyIDLength {
+		return toolkitError.ErrBadKeyID
+	}
+	if entry.KeyPair == nil {
+		return toolkitError.ErrNilKeyPair
+	}
+	if entry.Sign == nil {
+		sign, err := defaultCryptSign(entry.KeyPair)
+		if err != nil {
+			return err
+		}
+		entry.Sign = sign
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, existing := range k.entries {
+		if existing.ID == entry.ID {
+			return toolkitError.ErrDuplicateKeyID
+		}
+	}
+	k.entries = append(k.entries, &entry)
+	return nil
+}
+
+// Remove 移除密钥版本，返回是否存在
+func (k *Keyring) Remove(id string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	index := slices.IndexFunc(k.entries, func(entry *KeyringEntry) bool { return entry.ID == id })
+	if index < 0 {
+		return false
+	}
+	k.entries = slices.Delete(k.entries, index, index+1)
+	return true
+}
+
+// Current 获取当前用于签名的密钥版本
+func (k *Keyring) Current() (KeyringEntry, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	entry := k.current(time.Now())
+	if entry == nil {
+		return KeyringEntry{}, toolkitError.ErrNoActiveKey
+	}
+	return *entry, nil
+}
+
+// current 生效时间相同时以后添加的密钥为准
+func (k *Keyring) current(now time.Time) *KeyringEntry {
+	var current *KeyringEntry
+	for _, entry := range k.entries {
+		if entry.activeAt(now) && (current == nil || !entry.ActivateAt.Before(current.ActivateAt)) {
+			current = entry
+		}
+	}
+	return current
+}
+
+// Sign 使用当前密钥签名，返回包含密钥标识的签名信封
+func (k *Keyring) Sign(raw []byte) ([]byte, error) {
+	k.mu.RLock()
+	entry := k.current(time.Now())
+	k.mu.RUnlock()
+	if entry == nil {
+		return nil, toolkitError.ErrNoActiveKey
+	}
+	signature, err := entry.Sign.Sign(entry.KeyPair, raw)
+	if err != nil {
+		return nil, err
+	}
+	envelope := make([]byte, 0, 2+len(entry.ID)+len(signature))
+	envelope = append(envelope, signatureEnvelopeVersion, byte(len(entry.ID)))
+	envelope = append(envelope, entry.ID...)
+	return append(envelope, signature...), nil
+}
+
+// Verify 验证签名信封，返回匹配的密钥标识
+// 信封中的密钥标识为空时尝试所有有效密钥，密钥标识不存在或已失效时返回 ErrSigningKeyNotFound
+func (k *Keyring) Verify(raw, envelope []byte) (string, error) {
+	if len(envelope) < 2 || envelope[0] != signatureEnvelopeVersion || len(envelope) < 2+int(envelope[1]) {
+		return "", toolkitError.ErrInvalidSignatureEnvelope
+	}
+	id := string(envelope[2 : 2+int(envelope[1])])
+	signature := envelope[2+int(envelope[1]):]
+	if id == "" {
+		return k.VerifyRaw(raw, signature)
+	}
+
+	k.mu.RLock()
+	var entry *KeyringEntry
+	for _, candidate := range k.entries {
+		if candidate.ID == id && candidate.activeAt(time.Now()) {
+			entry = candidate
+			break
+		}
+	}
+	k.mu.RUnlock()
+	if entry == nil {
+		return "", toolkitError.ErrSigningKeyNotFound
+	}
+	if err := entry.Sign.Verify(entry.KeyPair, raw, signature); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// VerifyRaw 验证不带信封的签名，依次尝试所有有效密钥，返回匹配的密钥标识
+func (k *Keyring) VerifyRaw(raw, signature []byte) (string, error) {
+	k.mu.RLock()
+	now := time.Now()
+	candidates := make([]*KeyringEntry, 0, len(k.entries))
+	for _, entry := range k.entries {
+		if entry.activeAt(now) {
+			candidates = append(candidates, entry)
+		}
+	}
+	k.mu.RUnlock()
+	if len(candidates) == 0 {
+		return "", toolkitError.ErrNoActiveKey
+	}
+	for _, entry := range candidates {
+		if entry.Sign.Verify(entry.KeyPair, raw, signature) == nil {
+			return entry.ID, nil
+		}
+	}
+	return "", toolkitError.ErrVerifyFailed
+}
+
+// SignBase64 解码 Base64 原文后签名，并返回 Base64 签名信封
+func (k *Keyring) SignBase64(base64Raw string) (string, error) {
+	content, err := base64.StdEncoding.DecodeString(base64Raw)
+	if err != nil {
+		return "", err
+	}
+	result, err := k.Sign(content)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(result), nil
+}
+
+// VerifyBase64 解码 Base64 原文和签名信封后执行验签，返回匹配的密钥标识
+func (k *Keyring) VerifyBase64(base64Raw, base64Envelope string) (string, error) {
+	rawContent, err := base64.StdEncoding.DecodeString(base64Raw)
+	if err != nil {
+		return "", err
+	}
+	envelope, err := base64.StdEncoding.DecodeString(base64Envelope)
+	if err != nil {
+		return "", err
+	}
+	return k.Verify(rawContent, envelope)
+}
+
+// defaultCryptSign 按密钥类型选择默认签名实现
+func defaultCryptSign(keyPair KeyPair) (CryptSign, error) {
+	switch keyPair.PublicKey().(type) {
+	case *rsa.PublicKey:
+		return NewRsaSignWithPSSAndSHA256(), nil
+	case *ecdsa.PublicKey:
+		return NewEcdsaSign(sha256.New()), nil
+	case ed25519.PublicKey:
+		return NewEd25519Sign(), nil
+	case nil:
+		return nil, toolkitError.ErrNilPublicKey
+	default:
+		return nil, toolkitError.ErrUnsupportedKeyType
+	}
+}
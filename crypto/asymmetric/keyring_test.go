@@ -0,0 +1,115 @@
+package asymmetric
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	now := time.Now()
+	oldKey := newTestEcdsaKeyPair(t)
+	newKey := newTestEd25519KeyPair(t)
+	nextKey := newTestRsaKeyPair(t)
+	keyring, err := NewKeyring(
+		KeyringEntry{ID: "v1", KeyPair: oldKey, ActivateAt: now.Add(-time.Hour)},
+		KeyringEntry{ID: "v2", KeyPair: newKey, ActivateAt: now.Add(-time.Minute)},
+		KeyringEntry{ID: "v3", KeyPair: nextKey, ActivateAt: now.Add(time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	current, err := keyring.Current()
+	if err != nil || current.ID != "v2" {
+		t.Fatalf("expected v2 as current key, got %s, %v", current.ID, err)
+	}
+
+	raw := []byte("rotate me")
+	envelope, err := keyring.Sign(raw)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if id, err := keyring.Verify(raw, envelope); err != nil || id != "v2" {
+		t.Fatalf("expected v2 to verify, got %s, %v", id, err)
+	}
+	if _, err = keyring.Verify([]byte("tampered"), envelope); !errors.Is(err, toolkitError.ErrVerifyFailed) {
+		t.Fatalf("expected ErrVerifyFailed, got %v", err)
+	}
+
+	// 旧密钥签发的裸签名仍可验证，并返回匹配的密钥标识
+	oldSignature, err := NewEcdsaSign(sha256.New()).Sign(oldKey, raw)
+	if err != nil {
+		t.Fatalf("sign with old key: %v", err)
+	}
+	if id, err := keyring.VerifyRaw(raw, oldSignature); err != nil || id != "v1" {
+		t.Fatalf("expected v1 to match raw signature, got %s, %v", id, err)
+	}
+
+	// 未生效的密钥不参与验签
+	futureSignature, _ := NewRsaSignWithPSSAndSHA256().Sign(nextKey, raw)
+	if _, err = keyring.VerifyRaw(raw, futureSignature); !errors.Is(err, toolkitError.ErrVerifyFailed) {
+		t.Fatalf("expected inactive key to be skipped, got %v", err)
+	}
+	futureEnvelope := append([]byte{signatureEnvelopeVersion, 2, 'v', '3'}, futureSignature...)
+	if _, err = keyring.Verify(raw, futureEnvelope); !errors.Is(err, toolkitError.ErrSigningKeyNotFound) {
+		t.Fatalf("expected ErrSigningKeyNotFound, got %v", err)
+	}
+
+	// 移除当前密钥后回退到旧密钥
+	if !keyring.Remove("v2") || keyring.Remove("v2") {
+		t.Fatal("unexpected remove result")
+	}
+	if _, err = keyring.Verify(raw, envelope); !errors.Is(err, toolkitError.ErrSigningKeyNotFound) {
+		t.Fatalf("expected removed key to be unknown, got %v", err)
+	}
+	if current, _ = keyring.Current(); current.ID != "v1" {
+		t.Fatalf("expected v1 after removal, got %s", current.ID)
+	}
+}
+
+func TestKeyringExpiry(t *testing.T) {
+	keyPair := newTestEcdsaKeyPair(t)
+	keyring, err := NewKeyring(KeyringEntry{ID: "expired", KeyPair: keyPair, ExpireAt: time.Now().Add(-time.Second)})
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	if _, err = keyring.Sign([]byte("raw")); !errors.Is(err, toolkitError.ErrNoActiveKey) {
+		t.Fatalf("expected ErrNoActiveKey, got %v", err)
+	}
+	if _, err = keyring.VerifyRaw([]byte("raw"), []byte("sign")); !errors.Is(err, toolkitError.ErrNoActiveKey) {
+		t.Fatalf("expected ErrNoActiveKey, got %v", err)
+	}
+}
+
+func TestKeyringValidation(t *testing.T) {
+	keyPair := newTestEcdsaKeyPair(t)
+	keyring, err := NewKeyring(KeyringEntry{ID: "v1", KeyPair: keyPair})
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	if err = keyring.Add(KeyringEntry{ID: "v1", KeyPair: keyPair}); !errors.Is(err, toolkitError.ErrDuplicateKeyID) {
+		t.Fatalf("expected ErrDuplicateKeyID, got %v", err)
+	}
+	if err = keyring.Add(KeyringEntry{KeyPair: keyPair}); !errors.Is(err, toolkitError.ErrBadKeyID) {
+		t.Fatalf("expected ErrBadKeyID, got %v", err)
+	}
+	if err = keyring.Add(KeyringEntry{ID: "x25519", KeyPair: newTestX25519KeyPair(t)}); !errors.Is(err, toolkitError.ErrUnsupportedKeyType) {
+		t.Fatalf("expected ErrUnsupportedKeyType, got %v", err)
+	}
+	if _, err = keyring.Verify([]byte("raw"), []byte{signatureEnvelopeVersion, 10, 'a'}); !errors.Is(err, toolkitError.ErrInvalidSignatureEnvelope) {
+		t.Fatalf("expected ErrInvalidSignatureEnvelope, got %v", err)
+	}
+
+	base64Raw := base64.StdEncoding.EncodeToString([]byte("raw"))
+	base64Envelope, err := keyring.SignBase64(base64Raw)
+	if err != nil {
+		t.Fatalf("sign base64: %v", err)
+	}
+	if id, err := keyring.VerifyBase64(base64Raw, base64Envelope); err != nil || id != "v1" {
+		t.Fatalf("unexpected base64 verify result %s, %v", id, err)
+	}
+}
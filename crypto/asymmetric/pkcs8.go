@@ -0,0 +1,356 @@
+package asymmetric
+
+import (
+	"crypto/aes"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"hash"
+
+	"github.com/acexy/golang-toolkit/crypto/symmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/math/conversion"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// PKCS8KDFPBKDF2 使用 PBKDF2-HMAC-SHA256 派生密钥
+	PKCS8KDFPBKDF2 PKCS8KDF = iota
+	// PKCS8KDFScrypt 使用 scrypt 派生密钥
+	PKCS8KDFScrypt
+)
+
+const (
+	defaultPBKDF2Iterations = 600000
+	defaultScryptN          = 1 << 15
+	defaultScryptR          = 8
+	defaultScryptP          = 1
+	defaultPKCS8KeySize     = 32
+	pkcs8SaltSize           = 16
+
+	// 允许的最大派生参数，防止恶意密钥文件消耗过多资源
+	// scrypt 内存开销约为 128*N*r 字节，CPU 开销再乘以 p，因此限制二者组合而不是单独限制 N 和 r
+	maxPBKDF2Iterations = 10000000
+	maxScryptMemory     = 256 << 20
+	maxScryptP          = 16
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidScrypt         = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11591, 4, 11}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// PKCS8KDF 加密 PKCS#8 私钥时使用的密钥派生函数
+type PKCS8KDF int
+
+// PKCS8EncryptOption 表示加密 PKCS#8 私钥时使用的配置，零值使用 PBKDF2-HMAC-SHA256 和 AES-256-CBC
+type PKCS8EncryptOption struct {
+	// KDF 密钥派生函数
+	KDF PKCS8KDF
+	// Iterations PBKDF2 迭代次数，为 0 时默认 600000
+	Iterations int
+	// ScryptN scrypt CPU/内存开销参数，必须为 2 的幂，为 0 时默认 32768；内存开销 128*N*r 不能超过 256 MiB
+	ScryptN int
+	// ScryptR scrypt 块大小参数，为 0 时默认 8
+	ScryptR int
+	// ScryptP scrypt 并行参数，为 0 时默认 1，不能超过 16
+	ScryptP int
+	// KeySize AES 密钥长度，可选 16、24、32，为 0 时默认 32
+	KeySize int
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+type scryptParams struct {
+	Salt                     []byte
+	CostParameter            int
+	BlockSize                int
+	ParallelizationParameter int
+	KeyLength                int `asn1:"optional"`
+}
+
+// EncryptPKCS8PrivateKey 使用口令将 PKCS#8 DER 私钥加密为 PBES2 EncryptedPrivateKeyInfo DER
+func EncryptPKCS8PrivateKey(der, passphrase []byte, option PKCS8EncryptOption) ([]byte, error) {
+	keySize := option.KeySize
+	if keySize == 0 {
+		keySize = defaultPKCS8KeySize
+	}
+	cipherOID, err := aesCBCOID(keySize)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, pkcs8SaltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	var kdf pkix.AlgorithmIdentifier
+	var key []byte
+	switch option.KDF {
+	case PKCS8KDFPBKDF2:
+		iterations := option.Iterations
+		if iterations <= 0 {
+			iterations = defaultPBKDF2Iterations
+		}
+		if key, err = pbkdf2.Key(sha256.New, conversion.FromBytes(passphrase), salt, iterations, keySize); err != nil {
+			return nil, err
+		}
+		params, err := asn1.Marshal(pbkdf2Params{
+			Salt:           salt,
+			IterationCount: iterations,
+			PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+		})
+		if err != nil {
+			return nil, err
+		}
+		kdf = pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: params}}
+	case PKCS8KDFScrypt:
+		n, r, p := option.ScryptN, option.ScryptR, option.ScryptP
+		if n == 0 {
+			n = defaultScryptN
+		}
+		if r == 0 {
+			r = defaultScryptR
+		}
+		if p == 0 {
+			p = defaultScryptP
+		}
+		if !validScryptParams(n, r, p) {
+			return nil, toolkitError.ErrUnsupportedPKCS8Encryption
+		}
+		if key, err = scrypt.Key(passphrase, salt, n, r, p, keySize); err != nil {
+			return nil, err
+		}
+		params, err := asn1.Marshal(scryptParams{
+			Salt:                     salt,
+			CostParameter:            n,
+			BlockSize:                r,
+			ParallelizationParameter: p,
+		})
+		if err != nil {
+			return nil, err
+		}
+		kdf = pkix.AlgorithmIdentifier{Algorithm: oidScrypt, Parameters: asn1.RawValue{FullBytes: params}}
+	default:
+		return nil, toolkitError.ErrUnsupportedPKCS8Encryption
+	}
+
+	// symmetric.AESEncrypt 默认 CBC 模式输出 IV || 密文
+	crypt, err := symmetric.NewAES(key)
+	if err != nil {
+		return nil, err
+	}
+	result, err := crypt.Encrypt(der)
+	if err != nil {
+		return nil, err
+	}
+	iv, encrypted := result[:aes.BlockSize], result[aes.BlockSize:]
+	ivParams, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: kdf,
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: cipherOID, Parameters: asn1.RawValue{FullBytes: ivParams}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: encrypted,
+	})
+}
+
+// DecryptPKCS8PrivateKey 使用口令解密 PBES2 EncryptedPrivateKeyInfo DER，返回 PKCS#8 DER 私钥
+// 支持 PBKDF2(HMAC-SHA1/SHA256/SHA512) 和 scrypt 密钥派生，以及 AES-128/192/256-CBC 加密
+func DecryptPKCS8PrivateKey(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) > 0 {
+		return nil, toolkitError.ErrBadPrivateKey
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, toolkitError.ErrUnsupportedPKCS8Encryption
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, toolkitError.ErrBadPrivateKey
+	}
+	keySize, err := aesCBCKeySize(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var iv []byte
+	if _, err = asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, toolkitError.ErrBadPrivateKey
+	}
+	key, err := derivePKCS8Key(params.KeyDerivationFunc, passphrase, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	crypt, err := symmetric.NewAES(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := crypt.Decrypt(append(iv, info.EncryptedData...))
+	if err != nil {
+		return nil, toolkitError.ErrIncorrectPassphrase
+	}
+	// CBC 填充校验可能偶然通过，解析失败同样视为口令错误
+	if _, err = x509.ParsePKCS8PrivateKey(plain); err != nil {
+		return nil, toolkitError.ErrIncorrectPassphrase
+	}
+	return plain, nil
+}
+
+func derivePKCS8Key(kdf pkix.AlgorithmIdentifier, passphrase []byte, keySize int) ([]byte, error) {
+	switch {
+	case kdf.Algorithm.Equal(oidPBKDF2):
+		var params pbkdf2Params
+		if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+			return nil, toolkitError.ErrBadPrivateKey
+		}
+		if params.IterationCount <= 0 || params.IterationCount > maxPBKDF2Iterations ||
+			(params.KeyLength != 0 && params.KeyLength != keySize) {
+			return nil, toolkitError.ErrUnsupportedPKCS8Encryption
+		}
+		var hashFunc func() hash.Hash
+		switch {
+		case len(params.PRF.Algorithm) == 0 || params.PRF.Algorithm.Equal(oidHMACWithSHA1):
+			hashFunc = sha1.New
+		case params.PRF.Algorithm.Equal(oidHMACWithSHA256):
+			hashFunc = sha256.New
+		case params.PRF.Algorithm.Equal(oidHMACWithSHA512):
+			hashFunc = sha512.New
+		default:
+			return nil, toolkitError.ErrUnsupportedPKCS8Encryption
+		}
+		return pbkdf2.Key(hashFunc, conversion.FromBytes(passphrase), params.Salt, params.IterationCount, keySize)
+	case kdf.Algorithm.Equal(oidScrypt):
+		var params scryptParams
+		if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+			return nil, toolkitError.ErrBadPrivateKey
+		}
+		if !validScryptParams(params.CostParameter, params.BlockSize, params.ParallelizationParameter) ||
+			(params.KeyLength != 0 && params.KeyLength != keySize) {
+			return nil, toolkitError.ErrUnsupportedPKCS8Encryption
+		}
+		return scrypt.Key(passphrase, params.Salt, params.CostParameter, params.BlockSize, params.ParallelizationParameter, keySize)
+	default:
+		return nil, toolkitError.ErrUnsupportedPKCS8Encryption
+	}
+}
+
+// validScryptParams 校验 scrypt 参数，N 必须为大于 1 的 2 的幂，内存开销 128*N*r 不超过 maxScryptMemory
+func validScryptParams(n, r, p int) bool {
+	if n <= 1 || n&(n-1) != 0 || r <= 0 || p <= 0 || p > maxScryptP {
+		return false
+	}
+	return n <= maxScryptMemory/128/r
+}
+
+func aesCBCOID(keySize int) (asn1.ObjectIdentifier, error) {
+	switch keySize {
+	case 16:
+		return oidAES128CBC, nil
+	case 24:
+		return oidAES192CBC, nil
+	case 32:
+		return oidAES256CBC, nil
+	default:
+		return nil, toolkitError.ErrInvalidAESKeySize
+	}
+}
+
+func aesCBCKeySize(oid asn1.ObjectIdentifier) (int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16, nil
+	case oid.Equal(oidAES192CBC):
+		return 24, nil
+	case oid.Equal(oidAES256CBC):
+		return 32, nil
+	default:
+		return 0, toolkitError.ErrUnsupportedPKCS8Encryption
+	}
+}
+
+// ToEncryptedPKCS8PrivatePem 使用口令将私钥导出为加密的 PKCS8 PEM 格式，即 ENCRYPTED PRIVATE KEY
+// 适用于任意实现了 ToPKCS8PrivatePem 的 KeyPair
+func ToEncryptedPKCS8PrivatePem(keyPair KeyPair, passphrase []byte, option PKCS8EncryptOption) (string, error) {
+	priPem, err := keyPair.ToPKCS8PrivatePem()
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(conversion.ParseBytes(priPem))
+	if block == nil {
+		return "", toolkitError.ErrBadPrivateKey
+	}
+	encrypted, err := EncryptPKCS8PrivateKey(block.Bytes, passphrase, option)
+	if err != nil {
+		return "", err
+	}
+	return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{
+		Type:  "ENCRYPTED PRIVATE KEY",
+		Bytes: encrypted,
+	})), nil
+}
+
+// decryptPrivatePem 解密加密的 PKCS#8 或旧版 Proc-Type: 4,ENCRYPTED PEM 私钥，返回未加密的 PEM
+// 未加密的 PEM 原样返回，交由各 KeyManager 按类型解析
+func decryptPrivatePem(priPem string, passphrase []byte) (string, error) {
+	block, _ := pem.Decode(conversion.ParseBytes(priPem))
+	if block == nil {
+		return "", toolkitError.ErrBadPrivateKey
+	}
+	switch {
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		der, err := DecryptPKCS8PrivateKey(block.Bytes, passphrase)
+		if err != nil {
+			return "", err
+		}
+		return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+	case x509.IsEncryptedPEMBlock(block):
+		// 旧版 PEM 加密缺少完整性校验，仅用于读取存量密钥，不提供写入
+		der, err := x509.DecryptPEMBlock(block, passphrase)
+		if err != nil {
+			if errors.Is(err, x509.IncorrectPasswordError) {
+				return "", toolkitError.ErrIncorrectPassphrase
+			}
+			return "", err
+		}
+		return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der})), nil
+	default:
+		return priPem, nil
+	}
+}
@@ -165,6 +165,22 @@ func (r *RsaKeyManager) LoadPrivateKey(priPem string) (RsaKeyPair, error) {
 	return r.LoadKeyPair("", priPem)
 }
 
+// LoadEncryptedPrivateKey 使用口令从加密的 PKCS8 或旧版 Proc-Type 加密 PEM 加载 RSA 私钥，未加密的 PEM 同样可以加载
+func (r *RsaKeyManager) LoadEncryptedPrivateKey(priPem string, passphrase []byte) (RsaKeyPair, error) {
+	return r.LoadEncryptedKeyPair("", priPem, passphrase)
+}
+
+// LoadEncryptedKeyPair 使用口令解密私钥后加载 RSA 公私钥，并校验二者是否匹配
+func (r *RsaKeyManager) LoadEncryptedKeyPair(pubPem, priPem string, passphrase []byte) (RsaKeyPair, error) {
+	if priPem != "" {
+		var err error
+		if priPem, err = decryptPrivatePem(priPem, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return r.LoadKeyPair(pubPem, priPem)
+}
+
 // LoadKeyPair 从 PEM 字符串加载 RSA 公私钥，并校验二者是否匹配
 func (r *RsaKeyManager) LoadKeyPair(pubPem, priPem string) (RsaKeyPair, error) {
 	if pubPem == "" && priPem == "" {
@@ -0,0 +1,193 @@
+package asymmetric
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+
+	"github.com/acexy/golang-toolkit/crypto/symmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// NewX25519KeyManager 创建 X25519 KeyManager
+func NewX25519KeyManager() *X25519KeyManager {
+	return &X25519KeyManager{}
+}
+
+// X25519KeyManager 管理 X25519 密钥创建、PEM 加载和密钥协商
+type X25519KeyManager struct{}
+
+// Create 创建新的 X25519 公私钥对
+func (x *X25519KeyManager) Create() (X25519KeyPair, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &x25519Key{
+		publicKey:  privateKey.PublicKey(),
+		privateKey: privateKey,
+	}, nil
+}
+
+// LoadPublicKey 从 PKIX PEM 字符串加载 X25519 公钥
+func (x *X25519KeyManager) LoadPublicKey(pubPem string) (X25519KeyPair, error) {
+	return x.LoadKeyPair(pubPem, "")
+}
+
+// LoadPrivateKey 从 PKCS8 PEM 字符串加载 X25519 私钥，并派生对应公钥
+func (x *X25519KeyManager) LoadPrivateKey(priPem string) (X25519KeyPair, error) {
+	return x.LoadKeyPair("", priPem)
+}
+
+// LoadKeyPair 从 PEM 字符串加载 X25519 公私钥，并校验二者是否匹配
+func (x *X25519KeyManager) LoadKeyPair(pubPem, priPem string) (X25519KeyPair, error) {
+	if pubPem == "" && priPem == "" {
+		return nil, toolkitError.ErrBadKey
+	}
+
+	var pub *ecdh.PublicKey
+	var pri *ecdh.PrivateKey
+
+	if pubPem != "" {
+		iface, err := parsePKIXPublicPem(pubPem)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		pub, ok = iface.(*ecdh.PublicKey)
+		if !ok || pub.Curve() != ecdh.X25519() {
+			return nil, toolkitError.ErrNotX25519PublicKey
+		}
+	}
+	if priPem != "" {
+		iface, err := parsePKCS8PrivatePem(priPem)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		pri, ok = iface.(*ecdh.PrivateKey)
+		if !ok || pri.Curve() != ecdh.X25519() {
+			return nil, toolkitError.ErrNotX25519PrivateKey
+		}
+	}
+	if pub == nil && pri != nil {
+		pub = pri.PublicKey()
+	}
+	if pub != nil && pri != nil && !pub.Equal(pri.PublicKey()) {
+		return nil, toolkitError.ErrKeyPairMismatch
+	}
+
+	return &x25519Key{
+		publicKey:  pub,
+		privateKey: pri,
+	}, nil
+}
+
+// SharedSecret 使用本方私钥和对方公钥计算 X25519 共享密钥
+// 共享密钥不应直接作为对称密钥使用，需要经过 KDF 派生，参见 DeriveAESKey
+func (x *X25519KeyManager) SharedSecret(keyPair, peer KeyPair) ([]byte, error) {
+	privateKey, err := loadX25519PrivateKey(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := loadX25519PublicKey(peer)
+	if err != nil {
+		return nil, err
+	}
+	return privateKey.ECDH(publicKey)
+}
+
+// DeriveAESKey 计算共享密钥并使用 HKDF-SHA256 派生 AES-256 密钥
+// 双方需使用相同的 salt 和 info，info 建议包含业务用途以区分不同场景派生的密钥
+func (x *X25519KeyManager) DeriveAESKey(keyPair, peer KeyPair, salt, info []byte) ([]byte, error) {
+	secret, err := x.SharedSecret(keyPair, peer)
+	if err != nil {
+		return nil, err
+	}
+	return deriveKey(secret, salt, info, derivedAESKeySize)
+}
+
+// DeriveAES 计算共享密钥并派生 AES-256 密钥，使用 option 创建 AES 加解密实例
+func (x *X25519KeyManager) DeriveAES(keyPair, peer KeyPair, salt, info []byte, option symmetric.AESOption) (*symmetric.AESEncrypt, error) {
+	key, err := x.DeriveAESKey(keyPair, peer, salt, info)
+	if err != nil {
+		return nil, err
+	}
+	return symmetric.NewAESWithOption(key, option)
+}
+
+func loadX25519PublicKey(keyPair KeyPair) (*ecdh.PublicKey, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	publicKey := keyPair.PublicKey()
+	if publicKey == nil {
+		return nil, toolkitError.ErrNilPublicKey
+	}
+	x25519PublicKey, ok := publicKey.(*ecdh.PublicKey)
+	if !ok || x25519PublicKey.Curve() != ecdh.X25519() {
+		return nil, toolkitError.ErrNotX25519PublicKey
+	}
+	return x25519PublicKey, nil
+}
+
+func loadX25519PrivateKey(keyPair KeyPair) (*ecdh.PrivateKey, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	privateKey := keyPair.PrivateKey()
+	if privateKey == nil {
+		return nil, toolkitError.ErrNilPrivateKey
+	}
+	x25519PrivateKey, ok := privateKey.(*ecdh.PrivateKey)
+	if !ok || x25519PrivateKey.Curve() != ecdh.X25519() {
+		return nil, toolkitError.ErrNotX25519PrivateKey
+	}
+	return x25519PrivateKey, nil
+}
+
+type x25519Key struct {
+	privateKey *ecdh.PrivateKey
+	publicKey  *ecdh.PublicKey
+}
+
+// PrivateKey 返回原始 X25519 私钥
+func (x *x25519Key) PrivateKey() any {
+	if x.privateKey == nil {
+		return nil
+	}
+	return x.privateKey
+}
+
+// PublicKey 返回原始 X25519 公钥
+func (x *x25519Key) PublicKey() any {
+	if x.publicKey == nil {
+		return nil
+	}
+	return x.publicKey
+}
+
+// ToPublicPem 将 X25519 公钥导出为默认 PKIX PEM 格式
+func (x *x25519Key) ToPublicPem() (string, error) {
+	return x.ToPKIXPublicPem()
+}
+
+// ToPrivatePem 将 X25519 私钥导出为默认 PKCS8 PEM 格式
+func (x *x25519Key) ToPrivatePem() (string, error) {
+	return x.ToPKCS8PrivatePem()
+}
+
+// ToPKIXPublicPem 将 X25519 公钥导出为 PKIX PEM 格式
+func (x *x25519Key) ToPKIXPublicPem() (string, error) {
+	if x.publicKey == nil {
+		return "", toolkitError.ErrNilPublicKey
+	}
+	return marshalPKIXPublicPem(x.publicKey)
+}
+
+// ToPKCS8PrivatePem 将 X25519 私钥导出为 PKCS8 PEM 格式
+func (x *x25519Key) ToPKCS8PrivatePem() (string, error) {
+	if x.privateKey == nil {
+		return "", toolkitError.ErrNilPrivateKey
+	}
+	return marshalPKCS8PrivatePem(x.privateKey)
+}
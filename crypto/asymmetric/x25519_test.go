@@ -0,0 +1,100 @@
+package asymmetric
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/acexy/golang-toolkit/crypto/symmetric"
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestX25519KeyPair(t *testing.T) X25519KeyPair {
+	t.Helper()
+
+	keyPair, err := NewX25519KeyManager().Create()
+	if err != nil {
+		t.Fatalf("create x25519 key pair: %v", err)
+	}
+	return keyPair
+}
+
+func TestX25519PemRoundTrip(t *testing.T) {
+	keyPair := newTestX25519KeyPair(t)
+	privatePem, err := keyPair.ToPrivatePem()
+	if err != nil {
+		t.Fatalf("export private pem: %v", err)
+	}
+
+	manager := NewX25519KeyManager()
+	loaded, err := manager.LoadKeyPair(mustPublicPem(t, keyPair), privatePem)
+	if err != nil {
+		t.Fatalf("load x25519 key pair: %v", err)
+	}
+	if !samePublicKey(keyPair.PublicKey(), loaded.PublicKey()) {
+		t.Fatal("expected loaded public key to match")
+	}
+	if _, err = manager.LoadKeyPair(mustPublicPem(t, newTestX25519KeyPair(t)), privatePem); !errors.Is(err, toolkitError.ErrKeyPairMismatch) {
+		t.Fatalf("expected ErrKeyPairMismatch, got %v", err)
+	}
+	if _, err = manager.LoadPublicKey(mustPublicPem(t, newTestEd25519KeyPair(t))); !errors.Is(err, toolkitError.ErrNotX25519PublicKey) {
+		t.Fatalf("expected ErrNotX25519PublicKey, got %v", err)
+	}
+}
+
+func TestX25519DeriveAES(t *testing.T) {
+	manager := NewX25519KeyManager()
+	alice := newTestX25519KeyPair(t)
+	bob := newTestX25519KeyPair(t)
+	bobPublic, err := manager.LoadPublicKey(mustPublicPem(t, bob))
+	if err != nil {
+		t.Fatalf("load bob public key: %v", err)
+	}
+	alicePublic, err := manager.LoadPublicKey(mustPublicPem(t, alice))
+	if err != nil {
+		t.Fatalf("load alice public key: %v", err)
+	}
+
+	aliceSecret, err := manager.SharedSecret(alice, bobPublic)
+	if err != nil {
+		t.Fatalf("alice shared secret: %v", err)
+	}
+	bobSecret, err := manager.SharedSecret(bob, alicePublic)
+	if err != nil || !bytes.Equal(aliceSecret, bobSecret) {
+		t.Fatalf("expected equal shared secrets, %v", err)
+	}
+
+	info := []byte("toolkit x25519 test")
+	option := symmetric.AESOption{Mode: symmetric.AESModeGCM}
+	aliceAES, err := manager.DeriveAES(alice, bobPublic, nil, info, option)
+	if err != nil {
+		t.Fatalf("alice derive aes: %v", err)
+	}
+	bobAES, err := manager.DeriveAES(bob, alicePublic, nil, info, option)
+	if err != nil {
+		t.Fatalf("bob derive aes: %v", err)
+	}
+	cipherData, err := aliceAES.Encrypt([]byte("hello bob"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	plain, err := bobAES.Decrypt(cipherData)
+	if err != nil || string(plain) != "hello bob" {
+		t.Fatalf("unexpected decrypted %q, %v", plain, err)
+	}
+
+	otherKey, err := manager.DeriveAESKey(alice, bobPublic, nil, []byte("other purpose"))
+	if err != nil {
+		t.Fatalf("derive aes key: %v", err)
+	}
+	sameKey, _ := manager.DeriveAESKey(bob, alicePublic, nil, info)
+	if len(otherKey) != 32 || bytes.Equal(otherKey, sameKey) {
+		t.Fatal("expected info to separate derived keys")
+	}
+	if _, err = manager.SharedSecret(bobPublic, alicePublic); !errors.Is(err, toolkitError.ErrNilPrivateKey) {
+		t.Fatalf("expected ErrNilPrivateKey, got %v", err)
+	}
+	if _, err = manager.SharedSecret(alice, newTestEcdsaKeyPair(t)); !errors.Is(err, toolkitError.ErrNotX25519PublicKey) {
+		t.Fatalf("expected ErrNotX25519PublicKey, got %v", err)
+	}
+}
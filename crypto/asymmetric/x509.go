@@ -0,0 +1,413 @@
+package asymmetric
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+	"github.com/acexy/golang-toolkit/math/conversion"
+)
+
+const (
+	// defaultCertificateValidity 未指定有效期时证书的默认有效期
+	defaultCertificateValidity = 365 * 24 * time.Hour
+	// certificateClockSkew 未指定生效时间时向前预留的时间，避免节点间时钟偏差导致证书尚未生效
+	certificateClockSkew = 5 * time.Minute
+)
+
+// CertificateTemplate 表示创建证书时使用的配置
+type CertificateTemplate struct {
+	// Subject 证书主体
+	Subject pkix.Name
+	// DNSNames 证书适用的域名
+	DNSNames []string
+	// IPAddresses 证书适用的 IP 地址
+	IPAddresses []net.IP
+	// EmailAddresses 证书适用的邮箱地址
+	EmailAddresses []string
+	// SerialNumber 证书序列号，为空时随机生成
+	SerialNumber *big.Int
+	// NotBefore 证书生效时间，为零值时使用当前时间之前 5 分钟
+	NotBefore time.Time
+	// Validity 证书有效期，为 0 时默认一年，由 CA 签发时过期时间不会晚于 CA 证书的过期时间
+	Validity time.Duration
+	// IsCA 是否为 CA 证书
+	IsCA bool
+	// MaxPathLen CA 证书允许的下级 CA 层数，为 0 时不限制，为负数时不允许签发下级 CA
+	MaxPathLen int
+	// KeyUsage 密钥用途，为 0 时按是否为 CA 和公钥类型设置默认值，仅 RSA 公钥默认包含 KeyEncipherment
+	KeyUsage x509.KeyUsage
+	// ExtKeyUsage 扩展密钥用途，非 CA 证书为空时默认同时用于服务端和客户端认证
+	ExtKeyUsage []x509.ExtKeyUsage
+}
+
+// CertificateRequestTemplate 表示创建证书签名请求时使用的配置
+type CertificateRequestTemplate struct {
+	// Subject 申请的证书主体
+	Subject pkix.Name
+	// DNSNames 申请的域名
+	DNSNames []string
+	// IPAddresses 申请的 IP 地址
+	IPAddresses []net.IP
+	// EmailAddresses 申请的邮箱地址
+	EmailAddresses []string
+}
+
+// VerifyCertificateOption 表示验证证书链时使用的配置
+type VerifyCertificateOption struct {
+	// DNSName 需要匹配的域名或 IP，为空时不校验
+	DNSName string
+	// CurrentTime 验证使用的时间，为零值时使用当前时间
+	CurrentTime time.Time
+	// KeyUsages 证书需要满足的扩展密钥用途，为空时默认为服务端认证，传入 x509.ExtKeyUsageAny 可跳过校验
+	KeyUsages []x509.ExtKeyUsage
+}
+
+// Certificate X.509 证书
+type Certificate struct {
+	cert *x509.Certificate
+}
+
+// CertificateRequest X.509 证书签名请求
+type CertificateRequest struct {
+	csr *x509.CertificateRequest
+}
+
+// X509 获取原始证书
+func (c *Certificate) X509() *x509.Certificate {
+	return c.cert
+}
+
+// PublicKey 获取证书中的原始公钥
+func (c *Certificate) PublicKey() any {
+	return c.cert.PublicKey
+}
+
+// ToPem 将证书导出为 PEM 格式
+func (c *Certificate) ToPem() string {
+	return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: c.cert.Raw,
+	}))
+}
+
+// TLSCertificate 将证书和对应的私钥组合为 tls.Certificate，chain 为需要一并发送的中间证书
+func (c *Certificate) TLSCertificate(keyPair KeyPair, chain ...*Certificate) (tls.Certificate, error) {
+	signer, err := loadSigner(keyPair)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if !samePublicKey(signer.Public(), c.cert.PublicKey) {
+		return tls.Certificate{}, toolkitError.ErrKeyPairMismatch
+	}
+	certificate := tls.Certificate{
+		Certificate: [][]byte{c.cert.Raw},
+		PrivateKey:  signer,
+		Leaf:        c.cert,
+	}
+	for _, cert := range chain {
+		certificate.Certificate = append(certificate.Certificate, cert.cert.Raw)
+	}
+	return certificate, nil
+}
+
+// X509 获取原始证书签名请求
+func (c *CertificateRequest) X509() *x509.CertificateRequest {
+	return c.csr
+}
+
+// PublicKey 获取证书签名请求中的原始公钥
+func (c *CertificateRequest) PublicKey() any {
+	return c.csr.PublicKey
+}
+
+// ToPem 将证书签名请求导出为 PEM 格式
+func (c *CertificateRequest) ToPem() string {
+	return conversion.FromBytes(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: c.csr.Raw,
+	}))
+}
+
+// CreateSelfSignedCertificate 使用公私钥创建自签名证书，通常用于根 CA 或测试证书
+func CreateSelfSignedCertificate(keyPair KeyPair, template CertificateTemplate) (*Certificate, error) {
+	signer, err := loadSigner(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := newCertificateTemplate(template, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	return createCertificate(cert, cert, signer.Public(), signer)
+}
+
+// CreateSelfSignedCA 创建自签名的根 CA 证书
+func CreateSelfSignedCA(keyPair KeyPair, template CertificateTemplate) (*Certificate, error) {
+	template.IsCA = true
+	return CreateSelfSignedCertificate(keyPair, template)
+}
+
+// IssueCertificate 使用 CA 证书和私钥为 subject 的公钥签发证书
+func IssueCertificate(issuer *Certificate, issuerKeyPair KeyPair, subject KeyPair, template CertificateTemplate) (*Certificate, error) {
+	if subject == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	publicKey := subject.PublicKey()
+	if publicKey == nil {
+		return nil, toolkitError.ErrNilPublicKey
+	}
+	return issue(issuer, issuerKeyPair, publicKey, template)
+}
+
+// IssueCertificateFromRequest 根据证书签名请求签发证书
+// template 中未设置的主体和 SAN 信息使用请求中的内容
+func IssueCertificateFromRequest(issuer *Certificate, issuerKeyPair KeyPair, request *CertificateRequest, template CertificateTemplate) (*Certificate, error) {
+	if request == nil || request.csr == nil {
+		return nil, toolkitError.ErrBadCertificateRequest
+	}
+	if err := request.csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+	if template.Subject.String() == "" {
+		template.Subject = request.csr.Subject
+	}
+	if len(template.DNSNames) == 0 {
+		template.DNSNames = request.csr.DNSNames
+	}
+	if len(template.IPAddresses) == 0 {
+		template.IPAddresses = request.csr.IPAddresses
+	}
+	if len(template.EmailAddresses) == 0 {
+		template.EmailAddresses = request.csr.EmailAddresses
+	}
+	return issue(issuer, issuerKeyPair, request.csr.PublicKey, template)
+}
+
+// CreateCertificateRequest 使用公私钥创建证书签名请求
+func CreateCertificateRequest(keyPair KeyPair, template CertificateRequestTemplate) (*CertificateRequest, error) {
+	signer, err := loadSigner(keyPair)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:        template.Subject,
+		DNSNames:       template.DNSNames,
+		IPAddresses:    template.IPAddresses,
+		EmailAddresses: template.EmailAddresses,
+	}, signer)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateRequest{csr: csr}, nil
+}
+
+// LoadCertificate 从 PEM 字符串加载第一个证书
+func LoadCertificate(certPem string) (*Certificate, error) {
+	certs, err := LoadCertificates(certPem)
+	if err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+// LoadCertificates 从 PEM 字符串加载所有证书，适用于证书链文件
+func LoadCertificates(certPem string) ([]*Certificate, error) {
+	rest := conversion.ParseBytes(certPem)
+	certs := make([]*Certificate, 0)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, &Certificate{cert: cert})
+	}
+	if len(certs) == 0 {
+		return nil, toolkitError.ErrBadCertificate
+	}
+	return certs, nil
+}
+
+// LoadCertificateRequest 从 PEM 字符串加载证书签名请求并校验其签名
+func LoadCertificateRequest(csrPem string) (*CertificateRequest, error) {
+	block, _ := pem.Decode(conversion.ParseBytes(csrPem))
+	if block == nil || (block.Type != "CERTIFICATE REQUEST" && block.Type != "NEW CERTIFICATE REQUEST") {
+		return nil, toolkitError.ErrBadCertificateRequest
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+	return &CertificateRequest{csr: csr}, nil
+}
+
+// VerifyCertificateChain 使用受信任的根证书和中间证书验证证书，返回所有可用的证书链
+func VerifyCertificateChain(leaf *Certificate, intermediates, roots []*Certificate, option VerifyCertificateOption) ([][]*Certificate, error) {
+	if leaf == nil || leaf.cert == nil {
+		return nil, toolkitError.ErrBadCertificate
+	}
+	verifyOptions := x509.VerifyOptions{
+		DNSName:       option.DNSName,
+		CurrentTime:   option.CurrentTime,
+		KeyUsages:     option.KeyUsages,
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, root := range roots {
+		verifyOptions.Roots.AddCert(root.cert)
+	}
+	for _, intermediate := range intermediates {
+		verifyOptions.Intermediates.AddCert(intermediate.cert)
+	}
+	verified, err := leaf.cert.Verify(verifyOptions)
+	if err != nil {
+		return nil, err
+	}
+	chains := make([][]*Certificate, len(verified))
+	for i, chain := range verified {
+		chains[i] = make([]*Certificate, len(chain))
+		for j, cert := range chain {
+			chains[i][j] = &Certificate{cert: cert}
+		}
+	}
+	return chains, nil
+}
+
+func issue(issuer *Certificate, issuerKeyPair KeyPair, publicKey any, template CertificateTemplate) (*Certificate, error) {
+	if issuer == nil || issuer.cert == nil {
+		return nil, toolkitError.ErrBadCertificate
+	}
+	if !issuer.cert.IsCA {
+		return nil, toolkitError.ErrNotCACertificate
+	}
+	signer, err := loadSigner(issuerKeyPair)
+	if err != nil {
+		return nil, err
+	}
+	if !samePublicKey(signer.Public(), issuer.cert.PublicKey) {
+		return nil, toolkitError.ErrKeyPairMismatch
+	}
+	cert, err := newCertificateTemplate(template, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	// 下级证书不能比 CA 证书更晚过期
+	if cert.NotAfter.After(issuer.cert.NotAfter) {
+		cert.NotAfter = issuer.cert.NotAfter
+	}
+	return createCertificate(cert, issuer.cert, publicKey, signer)
+}
+
+func createCertificate(template, parent *x509.Certificate, publicKey any, signer crypto.Signer) (*Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{cert: cert}, nil
+}
+
+func newCertificateTemplate(template CertificateTemplate, publicKey any) (*x509.Certificate, error) {
+	serialNumber := template.SerialNumber
+	if serialNumber == nil {
+		var err error
+		serialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+		if err != nil {
+			return nil, err
+		}
+	}
+	notBefore := template.NotBefore
+	if notBefore.IsZero() {
+		notBefore = time.Now().Add(-certificateClockSkew)
+	}
+	validity := template.Validity
+	if validity <= 0 {
+		validity = defaultCertificateValidity
+	}
+
+	cert := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               template.Subject,
+		DNSNames:              template.DNSNames,
+		IPAddresses:           template.IPAddresses,
+		EmailAddresses:        template.EmailAddresses,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              template.KeyUsage,
+		ExtKeyUsage:           template.ExtKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  template.IsCA,
+	}
+	if template.IsCA {
+		switch {
+		case template.MaxPathLen > 0:
+			cert.MaxPathLen = template.MaxPathLen
+		case template.MaxPathLen < 0:
+			cert.MaxPathLenZero = true
+		default:
+			cert.MaxPathLen = -1
+		}
+		if cert.KeyUsage == 0 {
+			cert.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+		}
+	} else {
+		if cert.KeyUsage == 0 {
+			cert.KeyUsage = x509.KeyUsageDigitalSignature
+			// 密钥加密只适用于 RSA 密钥交换
+			if _, ok := publicKey.(*rsa.PublicKey); ok {
+				cert.KeyUsage |= x509.KeyUsageKeyEncipherment
+			}
+		}
+		if len(cert.ExtKeyUsage) == 0 {
+			cert.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+		}
+	}
+	return cert, nil
+}
+
+// loadSigner 获取可用于签名的原始私钥
+func loadSigner(keyPair KeyPair) (crypto.Signer, error) {
+	if keyPair == nil {
+		return nil, toolkitError.ErrNilKeyPair
+	}
+	privateKey := keyPair.PrivateKey()
+	if privateKey == nil {
+		return nil, toolkitError.ErrNilPrivateKey
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, toolkitError.ErrNotSigner
+	}
+	return signer, nil
+}
+
+func samePublicKey(pub1, pub2 any) bool {
+	key, ok := pub1.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(pub2)
+}
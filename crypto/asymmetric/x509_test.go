@@ -0,0 +1,209 @@
+package asymmetric
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestCA(t *testing.T) (*Certificate, RsaKeyPair) {
+	t.Helper()
+
+	caKey := newTestRsaKeyPair(t)
+	ca, err := CreateSelfSignedCA(caKey, CertificateTemplate{Subject: pkix.Name{CommonName: "Test Root CA"}})
+	if err != nil {
+		t.Fatalf("create self signed ca: %v", err)
+	}
+	return ca, caKey
+}
+
+func TestCertificateIssueAndVerify(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leafKey := newTestEcdsaKeyPair(t)
+
+	leaf, err := IssueCertificate(ca, caKey, leafKey, CertificateTemplate{
+		Subject:     pkix.Name{CommonName: "service.internal"},
+		DNSNames:    []string{"service.internal"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		Validity:    time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("issue certificate: %v", err)
+	}
+	if !ca.X509().IsCA || leaf.X509().IsCA {
+		t.Fatal("unexpected basic constraints")
+	}
+
+	chains, err := VerifyCertificateChain(leaf, nil, []*Certificate{ca}, VerifyCertificateOption{DNSName: "service.internal"})
+	if err != nil || len(chains) != 1 || len(chains[0]) != 2 {
+		t.Fatalf("unexpected verified chains %v, %v", chains, err)
+	}
+	if _, err = VerifyCertificateChain(leaf, nil, []*Certificate{ca}, VerifyCertificateOption{DNSName: "other.internal"}); err == nil {
+		t.Fatal("expected hostname mismatch")
+	}
+	if _, err = VerifyCertificateChain(leaf, nil, []*Certificate{ca}, VerifyCertificateOption{CurrentTime: time.Now().Add(2 * time.Hour)}); err == nil {
+		t.Fatal("expected expired certificate")
+	}
+	otherCA, _ := newTestCA(t)
+	if _, err = VerifyCertificateChain(leaf, nil, []*Certificate{otherCA}, VerifyCertificateOption{}); err == nil {
+		t.Fatal("expected unknown authority")
+	}
+}
+
+func TestCertificateDefaultsFollowKeyAndIssuer(t *testing.T) {
+	ca, caKey := newTestCA(t)
+
+	ecdsaLeaf, err := IssueCertificate(ca, caKey, newTestEcdsaKeyPair(t), CertificateTemplate{Validity: 10 * 365 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("issue ecdsa certificate: %v", err)
+	}
+	if ecdsaLeaf.X509().KeyUsage != x509.KeyUsageDigitalSignature {
+		t.Fatalf("unexpected ecdsa key usage %v", ecdsaLeaf.X509().KeyUsage)
+	}
+	// 有效期超过 CA 时过期时间与 CA 对齐
+	if !ecdsaLeaf.X509().NotAfter.Equal(ca.X509().NotAfter) {
+		t.Fatalf("expected leaf to expire with ca at %v, got %v", ca.X509().NotAfter, ecdsaLeaf.X509().NotAfter)
+	}
+
+	rsaLeaf, err := IssueCertificate(ca, caKey, newTestRsaKeyPair(t), CertificateTemplate{Validity: time.Hour})
+	if err != nil {
+		t.Fatalf("issue rsa certificate: %v", err)
+	}
+	if rsaLeaf.X509().KeyUsage != x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment {
+		t.Fatalf("unexpected rsa key usage %v", rsaLeaf.X509().KeyUsage)
+	}
+	if !rsaLeaf.X509().NotAfter.Before(ca.X509().NotAfter) {
+		t.Fatalf("expected short validity to be kept, got %v", rsaLeaf.X509().NotAfter)
+	}
+}
+
+func TestCertificateIntermediateChain(t *testing.T) {
+	root, rootKey := newTestCA(t)
+	intermediateKey := newTestEcdsaKeyPair(t)
+	intermediate, err := IssueCertificate(root, rootKey, intermediateKey, CertificateTemplate{
+		Subject:    pkix.Name{CommonName: "Test Intermediate CA"},
+		IsCA:       true,
+		MaxPathLen: -1,
+	})
+	if err != nil {
+		t.Fatalf("issue intermediate: %v", err)
+	}
+	leaf, err := IssueCertificate(intermediate, intermediateKey, newTestEcdsaKeyPair(t), CertificateTemplate{
+		Subject:     pkix.Name{CommonName: "client"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		t.Fatalf("issue leaf: %v", err)
+	}
+
+	chains, err := VerifyCertificateChain(leaf, []*Certificate{intermediate}, []*Certificate{root}, VerifyCertificateOption{
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil || len(chains[0]) != 3 {
+		t.Fatalf("unexpected intermediate chain %v, %v", chains, err)
+	}
+	if intermediate.X509().MaxPathLen != 0 || !intermediate.X509().MaxPathLenZero {
+		t.Fatal("expected intermediate to forbid further ca")
+	}
+	if _, err = IssueCertificate(leaf, intermediateKey, newTestEcdsaKeyPair(t), CertificateTemplate{}); !errors.Is(err, toolkitError.ErrNotCACertificate) {
+		t.Fatalf("expected ErrNotCACertificate, got %v", err)
+	}
+	if _, err = IssueCertificate(intermediate, rootKey, newTestEcdsaKeyPair(t), CertificateTemplate{}); !errors.Is(err, toolkitError.ErrKeyPairMismatch) {
+		t.Fatalf("expected ErrKeyPairMismatch, got %v", err)
+	}
+}
+
+func TestCertificateRequestRoundTrip(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leafKey := newTestRsaKeyPair(t)
+
+	request, err := CreateCertificateRequest(leafKey, CertificateRequestTemplate{
+		Subject:  pkix.Name{CommonName: "csr.internal", Organization: []string{"toolkit"}},
+		DNSNames: []string{"csr.internal"},
+	})
+	if err != nil {
+		t.Fatalf("create csr: %v", err)
+	}
+	loaded, err := LoadCertificateRequest(request.ToPem())
+	if err != nil {
+		t.Fatalf("load csr: %v", err)
+	}
+	if loaded.X509().Subject.CommonName != "csr.internal" {
+		t.Fatalf("unexpected csr subject %v", loaded.X509().Subject)
+	}
+
+	leaf, err := IssueCertificateFromRequest(ca, caKey, loaded, CertificateTemplate{})
+	if err != nil {
+		t.Fatalf("issue from csr: %v", err)
+	}
+	if leaf.X509().Subject.CommonName != "csr.internal" || leaf.X509().DNSNames[0] != "csr.internal" {
+		t.Fatalf("expected csr subject and sans to be used, got %v", leaf.X509().Subject)
+	}
+	if !samePublicKey(leafKey.PublicKey(), leaf.PublicKey()) {
+		t.Fatal("expected certificate to carry csr public key")
+	}
+	if _, err = LoadCertificateRequest(leaf.ToPem()); !errors.Is(err, toolkitError.ErrBadCertificateRequest) {
+		t.Fatalf("expected ErrBadCertificateRequest, got %v", err)
+	}
+}
+
+func TestCertificatePemAndTLS(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leafKey := newTestEcdsaKeyPair(t)
+	leaf, err := IssueCertificate(ca, caKey, leafKey, CertificateTemplate{DNSNames: []string{"localhost"}})
+	if err != nil {
+		t.Fatalf("issue certificate: %v", err)
+	}
+
+	certs, err := LoadCertificates(leaf.ToPem() + ca.ToPem())
+	if err != nil || len(certs) != 2 || !certs[0].X509().Equal(leaf.X509()) {
+		t.Fatalf("unexpected loaded chain %v, %v", certs, err)
+	}
+	if _, err = LoadCertificate("not a pem"); !errors.Is(err, toolkitError.ErrBadCertificate) {
+		t.Fatalf("expected ErrBadCertificate, got %v", err)
+	}
+
+	certificate, err := leaf.TLSCertificate(leafKey, ca)
+	if err != nil || len(certificate.Certificate) != 2 {
+		t.Fatalf("unexpected tls certificate %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.X509())
+	serverConn, clientConn := net.Pipe()
+	defer func() { _ = clientConn.Close() }()
+	go func() {
+		server := tls.Server(serverConn, &tls.Config{Certificates: []tls.Certificate{certificate}})
+		_ = server.Handshake()
+		_ = server.Close()
+	}()
+	client := tls.Client(clientConn, &tls.Config{RootCAs: pool, ServerName: "localhost"})
+	if err = client.Handshake(); err != nil {
+		t.Fatalf("tls handshake: %v", err)
+	}
+	if _, err = leaf.TLSCertificate(newTestEcdsaKeyPair(t)); !errors.Is(err, toolkitError.ErrKeyPairMismatch) {
+		t.Fatalf("expected ErrKeyPairMismatch, got %v", err)
+	}
+	publicOnly, err := NewEmptyEcdsaKeyManager().LoadPublicKey(mustPublicPem(t, leafKey))
+	if err != nil {
+		t.Fatalf("load public key: %v", err)
+	}
+	if _, err = CreateSelfSignedCertificate(publicOnly, CertificateTemplate{}); !errors.Is(err, toolkitError.ErrNilPrivateKey) {
+		t.Fatalf("expected ErrNilPrivateKey, got %v", err)
+	}
+}
+
+func mustPublicPem(t *testing.T, keyPair KeyPair) string {
+	t.Helper()
+
+	publicPem, err := keyPair.ToPublicPem()
+	if err != nil {
+		t.Fatalf("export public pem: %v", err)
+	}
+	return publicPem
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	"encoding/binary"
+	"fmt"
 	"sync"
 
 	toolkitError "github.com/acexy/golang-toolkit/error"
@@ -17,8 +22,13 @@ const (
 	AESModeCBC AESMode = iota
 	// AESModeGCM 表示 AES GCM 加密模式
 	AESModeGCM
+	// AESModeCTR 表示 AES CTR 加密模式，使用 HMAC-SHA256 认证密文（Encrypt-then-MAC）
+	AESModeCTR
 )
 
+// ctrMACSize CTR 模式 HMAC-SHA256 认证标签长度
+const ctrMACSize = sha256.Size
+
 // AESMode 表示 AES 加密模式
 type AESMode int
 
@@ -29,6 +39,10 @@ type AESEncrypt struct {
 	ivCreator      IVCreator      // IV规则
 	resultCreator  ResultCreator  // 结果返回规则
 	paddingCreator PaddingCreator // 填充规则
+	aadCreator     AADCreator     // 默认附加认证数据规则
+	chunkSize      int            // 流式加密分块大小
+	ctrKey         []byte         // CTR模式加密密钥，由key派生
+	macKey         []byte         // CTR模式HMAC密钥，由key派生
 	block          cipher.Block
 	blockErr       error
 	once           sync.Once
@@ -40,6 +54,11 @@ type AESOption struct {
 	IVCreator      IVCreator      // IV规则
 	ResultCreator  ResultCreator  // 结果返回规则
 	PaddingCreator PaddingCreator // 填充规则
+	// AADCreator 默认附加认证数据（AAD）规则，Encrypt/Decrypt 和 EncryptStream/DecryptStream 使用，仅 GCM 和 CTR 模式支持
+	// 只适用于实例级的固定 AAD，每次调用不同的上下文应使用 WithAAD 系列方法
+	AADCreator AADCreator
+	// StreamChunkSize 流式加解密分块大小，为 0 时使用 DefaultStreamChunkSize
+	StreamChunkSize int
 }
 
 // NewAES 创建默认 CBC 模式的 AES 加解密实例
@@ -57,6 +76,7 @@ func NewAES(key []byte) (*AESEncrypt, error) {
 		ivCreator:      &RandomIvCreator{},
 		resultCreator:  &AppendResultCreator{},
 		paddingCreator: &Pkcs7PaddingCreator{},
+		chunkSize:      DefaultStreamChunkSize,
 	}, nil
 }
 
@@ -80,10 +100,34 @@ func NewAESWithOption(key []byte, option AESOption) (*AESEncrypt, error) {
 		aesInstance.mode = AESModeCBC
 	case AESModeGCM:
 		aesInstance.mode = AESModeGCM
+	case AESModeCTR:
+		aesInstance.mode = AESModeCTR
+		ctrKey, macKey, err := deriveCTRKeys(keyCopy)
+		if err != nil {
+			return nil, err
+		}
+		aesInstance.ctrKey, aesInstance.macKey = ctrKey, macKey
 	default:
 		return nil, toolkitError.ErrUnsupportedAESMode
 	}
 
+	// 设置附加认证数据创建器（CBC模式不提供认证，无法绑定AAD）
+	if option.AADCreator != nil {
+		if aesInstance.mode == AESModeCBC {
+			return nil, toolkitError.ErrAADNotSupported
+		}
+		aesInstance.aadCreator = option.AADCreator
+	}
+
+	switch {
+	case option.StreamChunkSize == 0:
+		aesInstance.chunkSize = DefaultStreamChunkSize
+	case option.StreamChunkSize > 0 && option.StreamChunkSize <= maxStreamChunkSize:
+		aesInstance.chunkSize = option.StreamChunkSize
+	default:
+		return nil, toolkitError.ErrInvalidChunkSize
+	}
+
 	// 设置IV创建器 - GCM模式使用专门的nonce创建器
 	if option.IVCreator != nil {
 		aesInstance.ivCreator = option.IVCreator
@@ -138,6 +182,25 @@ type PaddingCreator interface {
 	UnPad(paddedData []byte) ([]byte, error)
 }
 
+// AADCreator 定义默认附加认证数据（AAD）规则
+// AAD 不会写入密文，但参与认证，解密时必须提供与加密时相同的 AAD
+// AADCreator 不接收调用上下文，只适用于实例级的固定 AAD（如服务名、数据类别）；
+// 需要绑定租户 ID、记录 ID 等每次调用不同的上下文时，应使用 EncryptWithAAD/DecryptWithAAD 等方法传入
+type AADCreator interface {
+	// CreateAAD 创建加密和解密时使用的附加认证数据，同一实例每次调用应返回相同结果
+	CreateAAD(key []byte) ([]byte, error)
+}
+
+// StaticAADCreator 使用固定的附加认证数据
+type StaticAADCreator struct {
+	AAD []byte
+}
+
+// CreateAAD 返回固定的附加认证数据
+func (s *StaticAADCreator) CreateAAD(key []byte) ([]byte, error) {
+	return s.AAD, nil
+}
+
 // RandomIvCreator 为 CBC 模式随机生成 IV
 // 该模式需要配合 AppendResultCreator 使用，解密时从密文前缀解析 IV。
 type RandomIvCreator struct{}
@@ -253,16 +316,59 @@ func (p *Pkcs7PaddingCreator) UnPad(paddedData []byte) ([]byte, error) {
 	return paddedData[:len(paddedData)-padding], nil
 }
 
+// deriveCTRKeys 使用 HKDF-SHA256 从原始密钥派生 CTR 加密密钥和 HMAC 密钥，避免同一密钥用于两种用途
+func deriveCTRKeys(key []byte) (ctrKey, macKey []byte, err error) {
+	ctrKey, err = hkdf.Key(sha256.New, key, nil, "toolkit aes-ctr encryption", len(key))
+	if err != nil {
+		return nil, nil, err
+	}
+	macKey, err = hkdf.Key(sha256.New, key, nil, "toolkit aes-ctr authentication", sha256.Size)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctrKey, macKey, nil
+}
+
 // cipherBlock 获取cipher.Block实例
 func (a *AESEncrypt) cipherBlock() (cipher.Block, error) {
 	a.once.Do(func() {
+		if a.mode == AESModeCTR {
+			a.block, a.blockErr = aes.NewCipher(a.ctrKey)
+			return
+		}
 		a.block, a.blockErr = aes.NewCipher(a.key)
 	})
 	return a.block, a.blockErr
 }
 
-// Encrypt 加密数据
+// defaultAAD 获取 AADCreator 创建的默认附加认证数据
+func (a *AESEncrypt) defaultAAD() ([]byte, error) {
+	if a.aadCreator == nil {
+		return nil, nil
+	}
+	aad, err := a.aadCreator.CreateAAD(a.key)
+	if err != nil {
+		return nil, toolkitError.ErrCreateAADFailed
+	}
+	return aad, nil
+}
+
+// errAuthFailed 认证失败错误，同时匹配 ErrAADMismatch 和 ErrMessageAuthFailed
+// 认证算法无法区分 AAD 不一致（包括加密时使用了 AAD 而解密时未提供）和密文被篡改，因此认证失败总是报告二者之一
+var errAuthFailed = fmt.Errorf("%w: %w", toolkitError.ErrAADMismatch, toolkitError.ErrMessageAuthFailed)
+
+// Encrypt 加密数据，配置了 AADCreator 时使用其创建的附加认证数据
 func (a *AESEncrypt) Encrypt(rawData []byte) ([]byte, error) {
+	aad, err := a.defaultAAD()
+	if err != nil {
+		return nil, err
+	}
+	return a.EncryptWithAAD(rawData, aad)
+}
+
+// EncryptWithAAD 使用指定的附加认证数据加密，aad 会替代 AADCreator 创建的默认值
+// 仅 GCM 和 CTR 模式支持非空 aad，解密时需使用 DecryptWithAAD 并提供相同的 aad
+func (a *AESEncrypt) EncryptWithAAD(rawData, aad []byte) ([]byte, error) {
 	if len(rawData) == 0 {
 		return nil, toolkitError.ErrEmptyEncryptData
 	}
@@ -274,9 +380,14 @@ func (a *AESEncrypt) Encrypt(rawData []byte) ([]byte, error) {
 
 	switch a.mode {
 	case AESModeCBC:
+		if len(aad) > 0 {
+			return nil, toolkitError.ErrAADNotSupported
+		}
 		return a.encryptCBC(block, rawData)
 	case AESModeGCM:
-		return a.encryptGCM(block, rawData)
+		return a.encryptGCM(block, rawData, aad)
+	case AESModeCTR:
+		return a.encryptCTR(block, rawData, aad)
 	default:
 		return nil, toolkitError.ErrUnsupportedAESMode
 	}
@@ -309,7 +420,7 @@ func (a *AESEncrypt) encryptCBC(block cipher.Block, rawData []byte) ([]byte, err
 }
 
 // encryptGCM GCM模式加密
-func (a *AESEncrypt) encryptGCM(block cipher.Block, rawData []byte) ([]byte, error) {
+func (a *AESEncrypt) encryptGCM(block cipher.Block, rawData, aad []byte) ([]byte, error) {
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
 		return nil, toolkitError.ErrCreateGCMFailed
@@ -327,12 +438,39 @@ func (a *AESEncrypt) encryptGCM(block cipher.Block, rawData []byte) ([]byte, err
 	}
 
 	// GCM加密（包含认证标签）
-	cipherData := gcm.Seal(nil, nonce, rawData, nil)
+	cipherData := gcm.Seal(nil, nonce, rawData, aad)
 
 	// 组合结果
 	return a.resultCreator.CombineResult(nonce, cipherData), nil
 }
 
+// encryptCTR CTR模式加密，密文后追加 HMAC-SHA256(AAD长度||AAD||IV||密文) 认证标签
+func (a *AESEncrypt) encryptCTR(block cipher.Block, rawData, aad []byte) ([]byte, error) {
+	iv, err := a.ivCreator.CreateForEncrypt(a.key, rawData)
+	if err != nil {
+		return nil, toolkitError.ErrCreateIVFailed
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, toolkitError.ErrInvalidIVSize
+	}
+
+	cipherData := make([]byte, len(rawData), len(rawData)+ctrMACSize)
+	cipher.NewCTR(block, iv).XORKeyStream(cipherData, rawData)
+	cipherData = append(cipherData, a.ctrTag(aad, iv, cipherData)...)
+
+	return a.resultCreator.CombineResult(iv, cipherData), nil
+}
+
+// ctrTag 计算 CTR 模式一次性加密的认证标签
+func (a *AESEncrypt) ctrTag(aad, iv, cipherData []byte) []byte {
+	mac := hmac.New(sha256.New, a.macKey)
+	mac.Write(binary.BigEndian.AppendUint64(nil, uint64(len(aad))))
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(cipherData)
+	return mac.Sum(nil)
+}
+
 // EncryptBase64 加密并返回Base64字符串
 func (a *AESEncrypt) EncryptBase64(rawData []byte) (string, error) {
 	cipherData, err := a.Encrypt(rawData)
@@ -342,8 +480,28 @@ func (a *AESEncrypt) EncryptBase64(rawData []byte) (string, error) {
 	return base64.StdEncoding.EncodeToString(cipherData), nil
 }
 
-// Decrypt 解密数据
+// EncryptBase64WithAAD 使用指定的附加认证数据加密并返回Base64字符串
+func (a *AESEncrypt) EncryptBase64WithAAD(rawData, aad []byte) (string, error) {
+	cipherData, err := a.EncryptWithAAD(rawData, aad)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(cipherData), nil
+}
+
+// Decrypt 解密数据，配置了 AADCreator 时使用其创建的附加认证数据
 func (a *AESEncrypt) Decrypt(cipherData []byte) ([]byte, error) {
+	aad, err := a.defaultAAD()
+	if err != nil {
+		return nil, err
+	}
+	return a.DecryptWithAAD(cipherData, aad)
+}
+
+// DecryptWithAAD 使用指定的附加认证数据解密，aad 会替代 AADCreator 创建的默认值
+// GCM 和 CTR 模式认证失败时返回同时匹配 ErrAADMismatch 和 ErrMessageAuthFailed 的错误，
+// aad 与加密时不一致（包括加密时使用了 AAD 而 aad 为空）或密文被篡改均会导致认证失败
+func (a *AESEncrypt) DecryptWithAAD(cipherData, aad []byte) ([]byte, error) {
 	if len(cipherData) == 0 {
 		return nil, toolkitError.ErrEmptyCipherData
 	}
@@ -355,9 +513,14 @@ func (a *AESEncrypt) Decrypt(cipherData []byte) ([]byte, error) {
 
 	switch a.mode {
 	case AESModeCBC:
+		if len(aad) > 0 {
+			return nil, toolkitError.ErrAADNotSupported
+		}
 		return a.decryptCBC(block, cipherData)
 	case AESModeGCM:
-		return a.decryptGCM(block, cipherData)
+		return a.decryptGCM(block, cipherData, aad)
+	case AESModeCTR:
+		return a.decryptCTR(block, cipherData, aad)
 	default:
 		return nil, toolkitError.ErrUnsupportedAESMode
 	}
@@ -394,7 +557,7 @@ func (a *AESEncrypt) decryptCBC(block cipher.Block, cipherData []byte) ([]byte,
 }
 
 // decryptGCM GCM模式解密
-func (a *AESEncrypt) decryptGCM(block cipher.Block, cipherData []byte) ([]byte, error) {
+func (a *AESEncrypt) decryptGCM(block cipher.Block, cipherData, aad []byte) ([]byte, error) {
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
 		return nil, toolkitError.ErrCreateGCMFailed
@@ -418,7 +581,39 @@ func (a *AESEncrypt) decryptGCM(block cipher.Block, cipherData []byte) ([]byte,
 	}
 
 	// GCM解密（包含认证验证）
-	return gcm.Open(nil, nonce, actualCipherData, nil)
+	rawData, err := gcm.Open(nil, nonce, actualCipherData, aad)
+	if err != nil {
+		return nil, errAuthFailed
+	}
+	return rawData, nil
+}
+
+// decryptCTR CTR模式解密，先校验认证标签再解密
+func (a *AESEncrypt) decryptCTR(block cipher.Block, cipherData, aad []byte) ([]byte, error) {
+	iv, err := a.ivCreator.ExtractForDecrypt(a.key, cipherData)
+	if err != nil {
+		return nil, toolkitError.ErrExtractIVFailed
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, toolkitError.ErrInvalidIVSize
+	}
+
+	actualCipherData, err := a.resultCreator.SeparateResult(cipherData, len(iv))
+	if err != nil {
+		return nil, toolkitError.ErrSeparateCipherDataFailed
+	}
+	if len(actualCipherData) < ctrMACSize {
+		return nil, toolkitError.ErrCipherDataTooShort
+	}
+
+	tagOffset := len(actualCipherData) - ctrMACSize
+	if !hmac.Equal(a.ctrTag(aad, iv, actualCipherData[:tagOffset]), actualCipherData[tagOffset:]) {
+		return nil, errAuthFailed
+	}
+
+	rawData := make([]byte, tagOffset)
+	cipher.NewCTR(block, iv).XORKeyStream(rawData, actualCipherData[:tagOffset])
+	return rawData, nil
 }
 
 // DecryptBase64 解密Base64字符串
@@ -435,3 +630,18 @@ func (a *AESEncrypt) DecryptBase64(base64CipherData string) (string, error) {
 
 	return string(plain), nil
 }
+
+// DecryptBase64WithAAD 使用指定的附加认证数据解密Base64字符串
+func (a *AESEncrypt) DecryptBase64WithAAD(base64CipherData string, aad []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(base64CipherData)
+	if err != nil {
+		return "", toolkitError.ErrInvalidBase64Data
+	}
+
+	plain, err := a.DecryptWithAAD(data, aad)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
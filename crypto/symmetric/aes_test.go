@@ -171,6 +171,136 @@ func TestAESGCMRejectsTamperedCipherData(t *testing.T) {
 	}
 }
 
+func TestAESCTRRoundTrip(t *testing.T) {
+	crypt, err := NewAESWithOption(generateTestKey(32), AESOption{Mode: AESModeCTR})
+	if err != nil {
+		t.Fatalf("new aes ctr: %v", err)
+	}
+
+	raw := []byte("hello aes ctr")
+	cipherData, err := crypt.Encrypt(raw)
+	if err != nil {
+		t.Fatalf("encrypt ctr: %v", err)
+	}
+	if len(cipherData) != aes.BlockSize+len(raw)+ctrMACSize {
+		t.Fatalf("unexpected ctr cipher data length %d", len(cipherData))
+	}
+	decrypted, err := crypt.Decrypt(cipherData)
+	if err != nil {
+		t.Fatalf("decrypt ctr: %v", err)
+	}
+	if !bytes.Equal(raw, decrypted) {
+		t.Fatalf("expected %q, got %q", raw, decrypted)
+	}
+
+	for _, index := range []int{0, aes.BlockSize, len(cipherData) - 1} {
+		tampered := bytes.Clone(cipherData)
+		tampered[index] ^= 1
+		if _, err = crypt.Decrypt(tampered); !errors.Is(err, toolkitError.ErrMessageAuthFailed) {
+			t.Fatalf("expected ErrMessageAuthFailed for byte %d, got %v", index, err)
+		}
+	}
+	if _, err = crypt.Decrypt(cipherData[:aes.BlockSize+ctrMACSize-1]); !errors.Is(err, toolkitError.ErrCipherDataTooShort) {
+		t.Fatalf("expected ErrCipherDataTooShort, got %v", err)
+	}
+}
+
+func TestAESWithAAD(t *testing.T) {
+	for _, mode := range []AESMode{AESModeGCM, AESModeCTR} {
+		crypt, err := NewAESWithOption(generateTestKey(32), AESOption{Mode: mode})
+		if err != nil {
+			t.Fatalf("new aes: %v", err)
+		}
+
+		raw := []byte("tenant secret")
+		cipherData, err := crypt.EncryptWithAAD(raw, []byte("tenant-1/record-1"))
+		if err != nil {
+			t.Fatalf("mode %d: encrypt with aad: %v", mode, err)
+		}
+		decrypted, err := crypt.DecryptWithAAD(cipherData, []byte("tenant-1/record-1"))
+		if err != nil || !bytes.Equal(raw, decrypted) {
+			t.Fatalf("mode %d: unexpected decrypted %q, %v", mode, decrypted, err)
+		}
+
+		_, err = crypt.DecryptWithAAD(cipherData, []byte("tenant-1/record-2"))
+		if !errors.Is(err, toolkitError.ErrAADMismatch) || !errors.Is(err, toolkitError.ErrMessageAuthFailed) {
+			t.Fatalf("mode %d: expected ErrAADMismatch, got %v", mode, err)
+		}
+		// 调用方遗漏上下文时同样报告 AAD 不一致
+		if _, err = crypt.DecryptWithAAD(cipherData, nil); !errors.Is(err, toolkitError.ErrAADMismatch) {
+			t.Fatalf("mode %d: expected ErrAADMismatch with nil aad, got %v", mode, err)
+		}
+		if _, err = crypt.Decrypt(cipherData); !errors.Is(err, toolkitError.ErrAADMismatch) || !errors.Is(err, toolkitError.ErrMessageAuthFailed) {
+			t.Fatalf("mode %d: expected ErrAADMismatch without aad, got %v", mode, err)
+		}
+
+		encoded, err := crypt.EncryptBase64WithAAD(raw, []byte("record-3"))
+		if err != nil {
+			t.Fatalf("mode %d: encrypt base64 with aad: %v", mode, err)
+		}
+		plain, err := crypt.DecryptBase64WithAAD(encoded, []byte("record-3"))
+		if err != nil || plain != string(raw) {
+			t.Fatalf("mode %d: unexpected base64 decrypted %q, %v", mode, plain, err)
+		}
+		if _, err = crypt.DecryptBase64WithAAD(encoded, []byte("record-4")); !errors.Is(err, toolkitError.ErrAADMismatch) {
+			t.Fatalf("mode %d: expected base64 ErrAADMismatch, got %v", mode, err)
+		}
+	}
+}
+
+func TestAESDefaultAADCreator(t *testing.T) {
+	crypt, err := NewAESWithOption(generateTestKey(32), AESOption{
+		Mode:       AESModeGCM,
+		AADCreator: &StaticAADCreator{AAD: []byte("tenant-1")},
+	})
+	if err != nil {
+		t.Fatalf("new aes: %v", err)
+	}
+	cipherData, err := crypt.Encrypt([]byte("default aad"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if decrypted, err := crypt.Decrypt(cipherData); err != nil || string(decrypted) != "default aad" {
+		t.Fatalf("unexpected decrypted %q, %v", decrypted, err)
+	}
+	if _, err = crypt.DecryptWithAAD(cipherData, []byte("tenant-1")); err != nil {
+		t.Fatalf("expected explicit aad to match default: %v", err)
+	}
+	if _, err = crypt.DecryptWithAAD(cipherData, []byte("tenant-2")); !errors.Is(err, toolkitError.ErrAADMismatch) {
+		t.Fatalf("expected ErrAADMismatch, got %v", err)
+	}
+
+	other, _ := NewAESWithOption(generateTestKey(32), AESOption{
+		Mode:       AESModeGCM,
+		AADCreator: &StaticAADCreator{AAD: []byte("tenant-2")},
+	})
+	if _, err = other.Decrypt(cipherData); !errors.Is(err, toolkitError.ErrAADMismatch) {
+		t.Fatalf("expected ErrAADMismatch across tenants, got %v", err)
+	}
+}
+
+func TestAESCBCRejectsAAD(t *testing.T) {
+	_, err := NewAESWithOption(generateTestKey(32), AESOption{AADCreator: &StaticAADCreator{AAD: []byte("aad")}})
+	if !errors.Is(err, toolkitError.ErrAADNotSupported) {
+		t.Fatalf("expected ErrAADNotSupported, got %v", err)
+	}
+
+	crypt, err := NewAES(generateTestKey(32))
+	if err != nil {
+		t.Fatalf("new aes: %v", err)
+	}
+	if _, err = crypt.EncryptWithAAD([]byte("raw"), []byte("aad")); !errors.Is(err, toolkitError.ErrAADNotSupported) {
+		t.Fatalf("expected ErrAADNotSupported, got %v", err)
+	}
+	cipherData, err := crypt.EncryptWithAAD([]byte("raw"), nil)
+	if err != nil {
+		t.Fatalf("encrypt with empty aad: %v", err)
+	}
+	if _, err = crypt.DecryptWithAAD(cipherData, []byte("aad")); !errors.Is(err, toolkitError.ErrAADNotSupported) {
+		t.Fatalf("expected ErrAADNotSupported, got %v", err)
+	}
+}
+
 func TestPkcs7PaddingRejectsInvalidContent(t *testing.T) {
 	padding := &Pkcs7PaddingCreator{}
 
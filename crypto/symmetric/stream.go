@@ -0,0 +1,398 @@
+package symmetric
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+// 加密流格式：version(1) | mode(1) | chunkSize(4) | IV/nonce | chunk...
+// 每个分块独立认证，认证数据包含流头部、AAD、分块序号和是否为最后一个分块，
+// 因此分块被重排、替换或在分块边界处截断时都能被检测到。
+// GCM 模式：分块 nonce 为基础 nonce 与 分块序号(4)|最后分块标记(1) 异或，分块为 密文|GCM 标签，
+// GCM 附加数据为 头部|AAD长度(8)|AAD
+// CTR 模式：所有分块共用同一个 CTR 密钥流，分块为 密文|HMAC-SHA256(头部|AAD长度(8)|AAD|分块序号|最后分块标记|密文)
+const (
+	streamVersion    byte = 1
+	streamHeaderSize      = 6
+
+	// DefaultStreamChunkSize 默认流式加密分块大小
+	DefaultStreamChunkSize = 64 * 1024
+	// maxStreamChunkSize 分块大小上限，避免解密时按头部声明的大小分配过多内存
+	maxStreamChunkSize = 16 * 1024 * 1024
+)
+
+// streamCipher 定义加密流单个分块的加解密规则，分块必须按序处理
+type streamCipher interface {
+	// seal 加密分块并追加到 dst
+	seal(dst, chunk []byte, counter uint32, final bool) []byte
+	// open 认证并解密分块，结果追加到 dst
+	open(dst, chunk []byte, counter uint32, final bool) ([]byte, error)
+	// overhead 每个分块的认证标签长度
+	overhead() int
+}
+
+// EncryptStream 创建加密写入器，写入的明文按分块加密后写入 dst
+// 仅支持 GCM 和 CTR 模式，IV/nonce 由 IVCreator 创建（rawData 参数为 nil）并写入流头部，不使用 ResultCreator；
+// 配置了 AADCreator 时使用其创建的附加认证数据
+// 写入完成后必须调用 Close 写出最后一个分块，Close 不会关闭 dst
+func (a *AESEncrypt) EncryptStream(dst io.Writer) (io.WriteCloser, error) {
+	aad, err := a.defaultAAD()
+	if err != nil {
+		return nil, err
+	}
+	return a.EncryptStreamWithAAD(dst, aad)
+}
+
+// EncryptStreamWithAAD 使用指定的附加认证数据创建加密写入器，aad 会替代 AADCreator 创建的默认值
+func (a *AESEncrypt) EncryptStreamWithAAD(dst io.Writer, aad []byte) (io.WriteCloser, error) {
+	block, err := a.cipherBlock()
+	if err != nil {
+		return nil, toolkitError.ErrCreateCipherBlockFailed
+	}
+
+	var iv []byte
+	switch a.mode {
+	case AESModeGCM:
+		if iv, err = a.ivCreator.CreateForEncrypt(a.key, nil); err != nil {
+			return nil, toolkitError.ErrCreateNonceFailed
+		}
+	case AESModeCTR:
+		if iv, err = a.ivCreator.CreateForEncrypt(a.key, nil); err != nil {
+			return nil, toolkitError.ErrCreateIVFailed
+		}
+	default:
+		return nil, toolkitError.ErrUnsupportedAESMode
+	}
+
+	header := make([]byte, streamHeaderSize, streamHeaderSize+len(iv))
+	header[0] = streamVersion
+	header[1] = byte(a.mode)
+	binary.BigEndian.PutUint32(header[2:], uint32(a.chunkSize))
+	header = append(header, iv...)
+
+	sc, err := a.newStreamCipher(block, header, iv, aad)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dst.Write(header); err != nil {
+		return nil, err
+	}
+	return &streamWriter{
+		dst:    dst,
+		cipher: sc,
+		buf:    make([]byte, 0, a.chunkSize),
+		out:    make([]byte, 0, a.chunkSize+sc.overhead()),
+	}, nil
+}
+
+// DecryptStream 创建解密读取器，从 src 读取 EncryptStream 生成的加密流
+// 每个分块认证通过后才会返回对应明文；只有 Read 返回 io.EOF 才表示整个流完整且未被截断，
+// 返回其他错误时，之前读取到的明文不应被视为完整数据
+func (a *AESEncrypt) DecryptStream(src io.Reader) (io.Reader, error) {
+	aad, err := a.defaultAAD()
+	if err != nil {
+		return nil, err
+	}
+	return a.DecryptStreamWithAAD(src, aad)
+}
+
+// DecryptStreamWithAAD 使用指定的附加认证数据创建解密读取器，aad 会替代 AADCreator 创建的默认值
+func (a *AESEncrypt) DecryptStreamWithAAD(src io.Reader, aad []byte) (io.Reader, error) {
+	block, err := a.cipherBlock()
+	if err != nil {
+		return nil, toolkitError.ErrCreateCipherBlockFailed
+	}
+
+	var ivSize int
+	switch a.mode {
+	case AESModeGCM:
+		ivSize = 12
+	case AESModeCTR:
+		ivSize = aes.BlockSize
+	default:
+		return nil, toolkitError.ErrUnsupportedAESMode
+	}
+
+	header := make([]byte, streamHeaderSize+ivSize)
+	if _, err = io.ReadFull(src, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, toolkitError.ErrInvalidStreamHeader
+		}
+		return nil, err
+	}
+	if header[0] != streamVersion || header[1] != byte(a.mode) {
+		return nil, toolkitError.ErrInvalidStreamHeader
+	}
+	chunkSize := binary.BigEndian.Uint32(header[2:])
+	if chunkSize == 0 || chunkSize > maxStreamChunkSize {
+		return nil, toolkitError.ErrInvalidStreamHeader
+	}
+
+	iv, err := a.ivCreator.ExtractForDecrypt(a.key, header[streamHeaderSize:])
+	if err != nil {
+		if a.mode == AESModeGCM {
+			return nil, toolkitError.ErrExtractNonceFailed
+		}
+		return nil, toolkitError.ErrExtractIVFailed
+	}
+	sc, err := a.newStreamCipher(block, header, iv, aad)
+	if err != nil {
+		return nil, err
+	}
+	return &streamReader{
+		src:    bufio.NewReader(src),
+		cipher: sc,
+		chunk:  make([]byte, int(chunkSize)+sc.overhead()),
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+// newStreamCipher 按加密模式创建分块加解密实现
+func (a *AESEncrypt) newStreamCipher(block cipher.Block, header, iv, aad []byte) (streamCipher, error) {
+	additionalData := make([]byte, 0, len(header)+8+len(aad))
+	additionalData = append(additionalData, header...)
+	additionalData = binary.BigEndian.AppendUint64(additionalData, uint64(len(aad)))
+	additionalData = append(additionalData, aad...)
+
+	switch a.mode {
+	case AESModeGCM:
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, toolkitError.ErrCreateGCMFailed
+		}
+		if len(iv) != gcm.NonceSize() {
+			return nil, toolkitError.ErrInvalidNonceSize
+		}
+		return &gcmStreamCipher{
+			aead:           gcm,
+			additionalData: additionalData,
+			base:           iv,
+			nonce:          make([]byte, len(iv)),
+		}, nil
+	case AESModeCTR:
+		if len(iv) != aes.BlockSize {
+			return nil, toolkitError.ErrInvalidIVSize
+		}
+		return &ctrStreamCipher{
+			stream:         cipher.NewCTR(block, iv),
+			mac:            hmac.New(sha256.New, a.macKey),
+			additionalData: additionalData,
+		}, nil
+	default:
+		return nil, toolkitError.ErrUnsupportedAESMode
+	}
+}
+
+// gcmStreamCipher GCM 模式分块加解密
+type gcmStreamCipher struct {
+	aead           cipher.AEAD
+	additionalData []byte
+	base           []byte
+	nonce          []byte
+}
+
+// chunkNonce 将分块序号和最后分块标记异或到基础 nonce 的末尾 5 字节
+func (g *gcmStreamCipher) chunkNonce(counter uint32, final bool) []byte {
+	copy(g.nonce, g.base)
+	n := len(g.nonce)
+	var suffix [5]byte
+	binary.BigEndian.PutUint32(suffix[:4], counter)
+	if final {
+		suffix[4] = 1
+	}
+	for i, b := range suffix {
+		g.nonce[n-5+i] ^= b
+	}
+	return g.nonce
+}
+
+func (g *gcmStreamCipher) seal(dst, chunk []byte, counter uint32, final bool) []byte {
+	return g.aead.Seal(dst, g.chunkNonce(counter, final), chunk, g.additionalData)
+}
+
+func (g *gcmStreamCipher) open(dst, chunk []byte, counter uint32, final bool) ([]byte, error) {
+	plain, err := g.aead.Open(dst, g.chunkNonce(counter, final), chunk, g.additionalData)
+	if err != nil {
+		return nil, errAuthFailed
+	}
+	return plain, nil
+}
+
+func (g *gcmStreamCipher) overhead() int {
+	return g.aead.Overhead()
+}
+
+// ctrStreamCipher CTR + HMAC-SHA256 模式分块加解密
+type ctrStreamCipher struct {
+	stream         cipher.Stream
+	mac            hash.Hash
+	additionalData []byte
+}
+
+// tag 计算分块认证标签并追加到 dst
+func (c *ctrStreamCipher) tag(dst, cipherData []byte, counter uint32, final bool) []byte {
+	var suffix [5]byte
+	binary.BigEndian.PutUint32(suffix[:4], counter)
+	if final {
+		suffix[4] = 1
+	}
+	c.mac.Reset()
+	c.mac.Write(c.additionalData)
+	c.mac.Write(suffix[:])
+	c.mac.Write(cipherData)
+	return c.mac.Sum(dst)
+}
+
+func (c *ctrStreamCipher) seal(dst, chunk []byte, counter uint32, final bool) []byte {
+	offset := len(dst)
+	dst = append(dst, chunk...)
+	c.stream.XORKeyStream(dst[offset:], chunk)
+	return c.tag(dst, dst[offset:], counter, final)
+}
+
+func (c *ctrStreamCipher) open(dst, chunk []byte, counter uint32, final bool) ([]byte, error) {
+	tagOffset := len(chunk) - ctrMACSize
+	var expected [ctrMACSize]byte
+	if !hmac.Equal(c.tag(expected[:0], chunk[:tagOffset], counter, final), chunk[tagOffset:]) {
+		return nil, errAuthFailed
+	}
+	offset := len(dst)
+	dst = append(dst, chunk[:tagOffset]...)
+	c.stream.XORKeyStream(dst[offset:], chunk[:tagOffset])
+	return dst, nil
+}
+
+func (c *ctrStreamCipher) overhead() int {
+	return ctrMACSize
+}
+
+// streamWriter 加密写入器，缓存明文直到凑满一个分块
+type streamWriter struct {
+	dst     io.Writer
+	cipher  streamCipher
+	buf     []byte
+	out     []byte
+	counter uint32
+	err     error
+	closed  bool
+}
+
+// Write 写入明文，凑满分块且还有后续数据时才加密输出，保证最后一个分块在 Close 时写出
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, toolkitError.ErrStreamClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	written := 0
+	for len(p) > 0 {
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(false); err != nil {
+				w.err = err
+				return written, err
+			}
+		}
+		n := min(cap(w.buf)-len(w.buf), len(p))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close 加密并写出最后一个分块，不会关闭底层 Writer
+func (w *streamWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	if w.err != nil {
+		return w.err
+	}
+	w.closed = true
+	return w.flush(true)
+}
+
+func (w *streamWriter) flush(final bool) error {
+	if !final && w.counter == math.MaxUint32 {
+		return toolkitError.ErrStreamTooLong
+	}
+	w.out = w.cipher.seal(w.out[:0], w.buf, w.counter, final)
+	if _, err := w.dst.Write(w.out); err != nil {
+		return err
+	}
+	w.counter++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// streamReader 解密读取器，逐个分块认证并解密
+type streamReader struct {
+	src     *bufio.Reader
+	cipher  streamCipher
+	chunk   []byte
+	buf     []byte
+	plain   []byte
+	counter uint32
+	err     error
+}
+
+// Read 读取已认证的明文
+func (r *streamReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.next()
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// next 读取并解密下一个分块，读到最后一个分块时返回 io.EOF
+// 分块不足完整长度或其后没有更多数据时视为最后一个分块
+func (r *streamReader) next() error {
+	n, err := io.ReadFull(r.src, r.chunk)
+	final := false
+	switch {
+	case err == nil:
+		if _, peekErr := r.src.Peek(1); peekErr != nil {
+			if !errors.Is(peekErr, io.EOF) {
+				return peekErr
+			}
+			final = true
+		}
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		final = true
+	default:
+		return err
+	}
+	if n < r.cipher.overhead() {
+		return toolkitError.ErrStreamTruncated
+	}
+	if !final && r.counter == math.MaxUint32 {
+		return toolkitError.ErrStreamTooLong
+	}
+
+	plain, err := r.cipher.open(r.buf[:0], r.chunk[:n], r.counter, final)
+	if err != nil {
+		return err
+	}
+	r.counter++
+	r.plain = plain
+	if final {
+		return io.EOF
+	}
+	return nil
+}
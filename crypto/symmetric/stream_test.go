@@ -0,0 +1,223 @@
+package symmetric
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	toolkitError "github.com/acexy/golang-toolkit/error"
+)
+
+func newTestStreamAES(t *testing.T, mode AESMode, chunkSize int) *AESEncrypt {
+	t.Helper()
+	crypt, err := NewAESWithOption(generateTestKey(32), AESOption{Mode: mode, StreamChunkSize: chunkSize})
+	if err != nil {
+		t.Fatalf("new aes: %v", err)
+	}
+	return crypt
+}
+
+func encryptStream(t *testing.T, crypt *AESEncrypt, raw []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := crypt.EncryptStream(&buf)
+	if err != nil {
+		t.Fatalf("encrypt stream: %v", err)
+	}
+	// 分多次写入，覆盖跨分块缓存
+	for len(raw) > 0 {
+		n := min(len(raw), 7)
+		if _, err = writer.Write(raw[:n]); err != nil {
+			t.Fatalf("write stream: %v", err)
+		}
+		raw = raw[n:]
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("close stream: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decryptStream(crypt *AESEncrypt, cipherData []byte) ([]byte, error) {
+	reader, err := crypt.DecryptStream(bytes.NewReader(cipherData))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(reader)
+}
+
+func TestAESStreamRoundTrip(t *testing.T) {
+	for _, mode := range []AESMode{AESModeGCM, AESModeCTR} {
+		crypt := newTestStreamAES(t, mode, 64)
+		for _, size := range []int{0, 1, 63, 64, 65, 128, 1000} {
+			raw := make([]byte, size)
+			if _, err := rand.Read(raw); err != nil {
+				t.Fatalf("rand: %v", err)
+			}
+			cipherData := encryptStream(t, crypt, raw)
+			decrypted, err := decryptStream(crypt, cipherData)
+			if err != nil {
+				t.Fatalf("mode %d size %d: decrypt stream: %v", mode, size, err)
+			}
+			if !bytes.Equal(raw, decrypted) {
+				t.Fatalf("mode %d size %d: decrypted stream mismatch", mode, size)
+			}
+		}
+	}
+}
+
+func TestAESStreamDefaultChunkSize(t *testing.T) {
+	crypt, err := NewAES(generateTestKey(32))
+	if err != nil {
+		t.Fatalf("new aes: %v", err)
+	}
+	if _, err = crypt.EncryptStream(io.Discard); !errors.Is(err, toolkitError.ErrUnsupportedAESMode) {
+		t.Fatalf("expected ErrUnsupportedAESMode for cbc stream, got %v", err)
+	}
+
+	crypt = newTestStreamAES(t, AESModeGCM, 0)
+	raw := bytes.Repeat([]byte("toolkit"), DefaultStreamChunkSize/3)
+	var buf bytes.Buffer
+	writer, err := crypt.EncryptStream(&buf)
+	if err != nil {
+		t.Fatalf("encrypt stream: %v", err)
+	}
+	if _, err = io.Copy(writer, bytes.NewReader(raw)); err != nil {
+		t.Fatalf("copy stream: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("close stream: %v", err)
+	}
+	if _, err = writer.Write([]byte("late")); !errors.Is(err, toolkitError.ErrStreamClosed) {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+	decrypted, err := decryptStream(crypt, buf.Bytes())
+	if err != nil || !bytes.Equal(raw, decrypted) {
+		t.Fatalf("unexpected decrypted stream: %v", err)
+	}
+}
+
+func TestAESStreamDetectsTampering(t *testing.T) {
+	for _, mode := range []AESMode{AESModeGCM, AESModeCTR} {
+		crypt := newTestStreamAES(t, mode, 16)
+		cipherData := encryptStream(t, crypt, bytes.Repeat([]byte("x"), 40))
+		headerSize := streamHeaderSize + 12
+		recordSize := 16 + 16
+		if mode == AESModeCTR {
+			headerSize = streamHeaderSize + 16
+			recordSize = 16 + ctrMACSize
+		}
+		if len(cipherData) != headerSize+2*recordSize+(8+recordSize-16) {
+			t.Fatalf("mode %d: unexpected stream length %d", mode, len(cipherData))
+		}
+
+		// 在分块边界截断
+		_, err := decryptStream(crypt, cipherData[:headerSize+recordSize])
+		if !errors.Is(err, toolkitError.ErrMessageAuthFailed) {
+			t.Fatalf("mode %d: expected truncated stream to fail authentication, got %v", mode, err)
+		}
+		// 只剩头部
+		if _, err = decryptStream(crypt, cipherData[:headerSize]); !errors.Is(err, toolkitError.ErrStreamTruncated) {
+			t.Fatalf("mode %d: expected ErrStreamTruncated, got %v", mode, err)
+		}
+		// 交换前两个分块
+		swapped := bytes.Clone(cipherData)
+		copy(swapped[headerSize:], cipherData[headerSize+recordSize:headerSize+2*recordSize])
+		copy(swapped[headerSize+recordSize:], cipherData[headerSize:headerSize+recordSize])
+		if _, err = decryptStream(crypt, swapped); !errors.Is(err, toolkitError.ErrMessageAuthFailed) {
+			t.Fatalf("mode %d: expected reordered stream to fail, got %v", mode, err)
+		}
+		// 篡改头部中的分块大小
+		tampered := bytes.Clone(cipherData)
+		tampered[5] = 32
+		if _, err = decryptStream(crypt, tampered); !errors.Is(err, toolkitError.ErrMessageAuthFailed) {
+			t.Fatalf("mode %d: expected tampered header to fail, got %v", mode, err)
+		}
+		// 追加数据
+		if _, err = decryptStream(crypt, append(bytes.Clone(cipherData), 0)); !errors.Is(err, toolkitError.ErrMessageAuthFailed) {
+			t.Fatalf("mode %d: expected appended stream to fail, got %v", mode, err)
+		}
+	}
+}
+
+func TestAESStreamRejectsInvalidHeader(t *testing.T) {
+	gcm := newTestStreamAES(t, AESModeGCM, 16)
+	ctr := newTestStreamAES(t, AESModeCTR, 16)
+	cipherData := encryptStream(t, gcm, []byte("header"))
+
+	if _, err := ctr.DecryptStream(bytes.NewReader(cipherData)); !errors.Is(err, toolkitError.ErrInvalidStreamHeader) {
+		t.Fatalf("expected mode mismatch to be rejected, got %v", err)
+	}
+	if _, err := gcm.DecryptStream(bytes.NewReader(cipherData[:4])); !errors.Is(err, toolkitError.ErrInvalidStreamHeader) {
+		t.Fatalf("expected short header to be rejected, got %v", err)
+	}
+	tampered := bytes.Clone(cipherData)
+	tampered[2] = 0xff
+	if _, err := gcm.DecryptStream(bytes.NewReader(tampered)); !errors.Is(err, toolkitError.ErrInvalidStreamHeader) {
+		t.Fatalf("expected oversized chunk to be rejected, got %v", err)
+	}
+	if _, err := NewAESWithOption(generateTestKey(32), AESOption{Mode: AESModeGCM, StreamChunkSize: -1}); !errors.Is(err, toolkitError.ErrInvalidChunkSize) {
+		t.Fatalf("expected ErrInvalidChunkSize, got %v", err)
+	}
+}
+
+func TestAESStreamWithAAD(t *testing.T) {
+	for _, mode := range []AESMode{AESModeGCM, AESModeCTR} {
+		newCrypt := func(aad string) *AESEncrypt {
+			crypt, err := NewAESWithOption(generateTestKey(32), AESOption{
+				Mode:            mode,
+				StreamChunkSize: 16,
+				AADCreator:      &StaticAADCreator{AAD: []byte(aad)},
+			})
+			if err != nil {
+				t.Fatalf("new aes: %v", err)
+			}
+			return crypt
+		}
+		raw := bytes.Repeat([]byte("aad"), 20)
+		cipherData := encryptStream(t, newCrypt("tenant-1"), raw)
+
+		decrypted, err := decryptStream(newCrypt("tenant-1"), cipherData)
+		if err != nil || !bytes.Equal(raw, decrypted) {
+			t.Fatalf("mode %d: unexpected decrypted stream: %v", mode, err)
+		}
+		if _, err = decryptStream(newCrypt("tenant-2"), cipherData); !errors.Is(err, toolkitError.ErrAADMismatch) {
+			t.Fatalf("mode %d: expected ErrAADMismatch, got %v", mode, err)
+		}
+		if _, err = decryptStream(newTestStreamAES(t, mode, 16), cipherData); !errors.Is(err, toolkitError.ErrAADMismatch) {
+			t.Fatalf("mode %d: expected ErrAADMismatch without aad, got %v", mode, err)
+		}
+	}
+}
+
+func TestAESStreamWithPerCallAAD(t *testing.T) {
+	crypt := newTestStreamAES(t, AESModeGCM, 16)
+	var buf bytes.Buffer
+	writer, err := crypt.EncryptStreamWithAAD(&buf, []byte("record-1"))
+	if err != nil {
+		t.Fatalf("encrypt stream with aad: %v", err)
+	}
+	if _, err = writer.Write([]byte("per call context")); err != nil {
+		t.Fatalf("write stream: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("close stream: %v", err)
+	}
+
+	reader, err := crypt.DecryptStreamWithAAD(bytes.NewReader(buf.Bytes()), []byte("record-1"))
+	if err != nil {
+		t.Fatalf("decrypt stream with aad: %v", err)
+	}
+	if plain, err := io.ReadAll(reader); err != nil || string(plain) != "per call context" {
+		t.Fatalf("unexpected decrypted stream %q, %v", plain, err)
+	}
+	reader, err = crypt.DecryptStreamWithAAD(bytes.NewReader(buf.Bytes()), []byte("record-2"))
+	if err != nil {
+		t.Fatalf("decrypt stream with aad: %v", err)
+	}
+	if _, err = io.ReadAll(reader); !errors.Is(err, toolkitError.ErrAADMismatch) {
+		t.Fatalf("expected ErrAADMismatch, got %v", err)
+	}
+}
@@ -36,6 +36,9 @@ var (
 	// ErrNilCurve 表示椭圆曲线为空
 	ErrNilCurve = errors.New("nil curve")
 
+	// ErrCurveMismatch 表示密钥协商双方使用的椭圆曲线不一致
+	ErrCurveMismatch = errors.New("curve mismatch")
+
 	// ErrNilHashFunction 表示 hash 函数为空
 	ErrNilHashFunction = errors.New("nil hash function")
 
@@ -51,9 +54,54 @@ var (
 	// ErrNotEcdsaPrivateKey 表示不是 ECDSA 私钥
 	ErrNotEcdsaPrivateKey = errors.New("not an ECDSA private key")
 
+	// ErrNotEd25519PublicKey 表示不是 Ed25519 公钥
+	ErrNotEd25519PublicKey = errors.New("not an Ed25519 public key")
+
+	// ErrNotEd25519PrivateKey 表示不是 Ed25519 私钥
+	ErrNotEd25519PrivateKey = errors.New("not an Ed25519 private key")
+
+	// ErrNotX25519PublicKey 表示不是 X25519 公钥
+	ErrNotX25519PublicKey = errors.New("not an X25519 public key")
+
+	// ErrNotX25519PrivateKey 表示不是 X25519 私钥
+	ErrNotX25519PrivateKey = errors.New("not an X25519 private key")
+
 	// ErrUnsupportedPaddingType 表示不支持的填充类型
 	ErrUnsupportedPaddingType = errors.New("not supported paddingType")
 
 	// ErrVerifyFailed 表示签名验证失败
 	ErrVerifyFailed = errors.New("verify failed")
+
+	// ErrNotSigner 表示私钥无法用于签名
+	ErrNotSigner = errors.New("private key is not a signer")
+
+	// ErrBadCertificate 表示证书内容无效
+	ErrBadCertificate = errors.New("bad certificate")
+
+	// ErrBadCertificateRequest 表示证书签名请求内容无效
+	ErrBadCertificateRequest = errors.New("bad certificate request")
+
+	// ErrNotCACertificate 表示签发者证书不是 CA 证书
+	ErrNotCACertificate = errors.New("not a CA certificate")
+
+	// ErrInvalidEnvelope 表示信封加密数据格式无效
+	ErrInvalidEnvelope = errors.New("invalid envelope")
+
+	// ErrBadKeyID 表示密钥标识为空或过长
+	ErrBadKeyID = errors.New("bad key id")
+
+	// ErrDuplicateKeyID 表示密钥标识已存在
+	ErrDuplicateKeyID = errors.New("duplicate key id")
+
+	// ErrNoActiveKey 表示没有处于有效期内的密钥
+	ErrNoActiveKey = errors.New("no active key")
+
+	// ErrInvalidSignatureEnvelope 表示签名信封格式无效
+	ErrInvalidSignatureEnvelope = errors.New("invalid signature envelope")
+
+	// ErrIncorrectPassphrase 表示私钥解密口令错误
+	ErrIncorrectPassphrase = errors.New("incorrect passphrase")
+
+	// ErrUnsupportedPKCS8Encryption 表示不支持的 PKCS#8 私钥加密算法或参数
+	ErrUnsupportedPKCS8Encryption = errors.New("unsupported PKCS#8 encryption")
 )
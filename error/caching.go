@@ -8,4 +8,52 @@ var (
 
 	// ErrBadBucketName 表示缓存桶名称无效或未注册
 	ErrBadBucketName = errors.New("bad bucket name")
+
+	// ErrTTLNotSupported 表示缓存桶不支持单独设置过期时间
+	ErrTTLNotSupported = errors.New("bucket does not support ttl")
+
+	// ErrUnsupportedEvictionPolicy 表示不支持的缓存淘汰策略
+	ErrUnsupportedEvictionPolicy = errors.New("unsupported eviction policy")
+
+	// ErrCacheEntryTooLarge 表示单个缓存项超过缓存桶容量上限
+	ErrCacheEntryTooLarge = errors.New("cache entry too large")
+
+	// ErrBatchSizeMismatch 表示批量操作的 key 与值数量不一致
+	ErrBatchSizeMismatch = errors.New("batch size mismatch")
+
+	// ErrUnsupportedCodec 表示缓存数据的编码头无法识别
+	ErrUnsupportedCodec = errors.New("unsupported cache codec")
+
+	// ErrUnsupportedCompression 表示不支持的缓存压缩算法
+	ErrUnsupportedCompression = errors.New("unsupported cache compression")
+
+	// ErrBucketNotIterable 表示缓存桶不支持遍历缓存项
+	ErrBucketNotIterable = errors.New("cache bucket not iterable")
+
+	// ErrInvalidSnapshot 表示缓存快照数据格式错误或已损坏
+	ErrInvalidSnapshot = errors.New("invalid cache snapshot")
+
+	// ErrUnsupportedSnapshotVersion 表示不支持的缓存快照版本
+	ErrUnsupportedSnapshotVersion = errors.New("unsupported cache snapshot version")
+
+	// ErrKeyArgsMismatch 表示构建缓存 key 时参数数量与声明的片段数量不一致
+	ErrKeyArgsMismatch = errors.New("cache key argument count mismatch")
+
+	// ErrKeyArgType 表示构建缓存 key 时参数类型与声明的片段类型不一致
+	ErrKeyArgType = errors.New("cache key argument type mismatch")
+
+	// ErrKeyArgSeparator 表示构建缓存 key 时字符串参数包含分隔符，会与其他 key 产生冲突
+	ErrKeyArgSeparator = errors.New("cache key argument contains separator")
+
+	// ErrInvalidationEnabled 表示缓存管理器已设置失效通知传输
+	ErrInvalidationEnabled = errors.New("cache invalidation already enabled")
+
+	// ErrInvalidationTooLarge 表示失效通知超过传输支持的最大长度
+	ErrInvalidationTooLarge = errors.New("cache invalidation event too large")
+
+	// ErrEncryptionKeyNotFound 表示找不到解密缓存值所需的密钥版本
+	ErrEncryptionKeyNotFound = errors.New("cache encryption key not found")
+
+	// ErrCacheValueNotEncrypted 表示 EncryptedCodec 读取到未加密的缓存值
+	ErrCacheValueNotEncrypted = errors.New("cache value not encrypted")
 )
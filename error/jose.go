@@ -0,0 +1,47 @@
+package error
+
+import "errors"
+
+var (
+	// ErrMalformedToken 表示 JWS/JWT 格式无效
+	ErrMalformedToken = errors.New("malformed token")
+
+	// ErrUnsupportedAlgorithm 表示不支持的 JOSE 签名算法
+	ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+
+	// ErrAlgorithmMismatch 表示令牌声明的算法与密钥配置的算法不一致
+	ErrAlgorithmMismatch = errors.New("algorithm mismatch")
+
+	// ErrUnsupportedCriticalHeader 表示令牌包含无法处理的 crit 头
+	ErrUnsupportedCriticalHeader = errors.New("unsupported critical header")
+
+	// ErrSigningKeyNotPrivate 表示签名密钥不包含私钥，只能用于验签
+	ErrSigningKeyNotPrivate = errors.New("signing key has no private key")
+
+	// ErrSigningKeyNotFound 表示找不到与令牌匹配的验签密钥
+	ErrSigningKeyNotFound = errors.New("signing key not found")
+
+	// ErrTokenExpired 表示令牌已过期
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrTokenNotYetValid 表示令牌尚未生效
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+
+	// ErrTokenIssuedInFuture 表示令牌签发时间晚于当前时间
+	ErrTokenIssuedInFuture = errors.New("token issued in the future")
+
+	// ErrTokenMissingExpiration 表示令牌缺少 exp 声明
+	ErrTokenMissingExpiration = errors.New("token missing expiration")
+
+	// ErrInvalidIssuer 表示令牌签发者不匹配
+	ErrInvalidIssuer = errors.New("invalid issuer")
+
+	// ErrInvalidAudience 表示令牌受众不匹配
+	ErrInvalidAudience = errors.New("invalid audience")
+
+	// ErrInvalidJWK 表示 JWK 内容无效
+	ErrInvalidJWK = errors.New("invalid JWK")
+
+	// ErrUnsupportedKeyType 表示不支持的密钥类型
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+)
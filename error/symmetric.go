@@ -55,4 +55,31 @@ var (
 
 	// ErrSeparateCipherDataFailed 表示分离密文失败
 	ErrSeparateCipherDataFailed = errors.New("failed to separate cipher data")
+
+	// ErrMessageAuthFailed 表示密文认证失败，密文可能被篡改或密钥错误
+	ErrMessageAuthFailed = errors.New("message authentication failed")
+
+	// ErrInvalidChunkSize 表示流式加密分块大小无效
+	ErrInvalidChunkSize = errors.New("invalid stream chunk size")
+
+	// ErrInvalidStreamHeader 表示加密流头部无效
+	ErrInvalidStreamHeader = errors.New("invalid stream header")
+
+	// ErrStreamTruncated 表示加密流被截断
+	ErrStreamTruncated = errors.New("stream truncated")
+
+	// ErrStreamTooLong 表示加密流分块数量超过上限
+	ErrStreamTooLong = errors.New("stream too long")
+
+	// ErrStreamClosed 表示向已关闭的加密流写入数据
+	ErrStreamClosed = errors.New("stream closed")
+
+	// ErrAADNotSupported 表示当前加密模式不支持附加认证数据
+	ErrAADNotSupported = errors.New("additional authenticated data not supported by AES mode")
+
+	// ErrCreateAADFailed 表示创建附加认证数据失败
+	ErrCreateAADFailed = errors.New("failed to create additional authenticated data")
+
+	// ErrAADMismatch 表示解密时提供的附加认证数据与加密时不一致，也可能是密文被篡改
+	ErrAADMismatch = errors.New("additional authenticated data mismatch")
 )